@@ -1,18 +1,20 @@
 package cmd
 
 import (
-	"fmt"
-	"net"
-	"os"
+	"time"
 
 	"github.com/lunarhue/libs-go/log"
+	"github.com/lunarhue/metallic-flock/pkg/agent"
 	"github.com/lunarhue/metallic-flock/pkg/config"
 	"github.com/lunarhue/metallic-flock/pkg/discovery"
+	"github.com/lunarhue/metallic-flock/pkg/fingerprint"
 	"github.com/lunarhue/metallic-flock/pkg/k3s"
+	"github.com/lunarhue/metallic-flock/pkg/pki"
 	"github.com/lunarhue/metallic-flock/pkg/proto"
 	pb "github.com/lunarhue/metallic-flock/pkg/proto/adoption/v1"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 var agentCmd = &cobra.Command{
@@ -24,32 +26,107 @@ var agentCmd = &cobra.Command{
 			log.Panicf("Failed to load config: %v", err)
 		}
 
-		hostname, _ := os.Hostname()
-		// Verify that the prerequisites are met
-		if !noVerify {
-			if err := k3s.VerifyK3sInstallation("agent"); err != nil {
-				log.Panicf("K3s verification failed: %v", err)
-			}
-		}
+		runAgent(cfg)
+	},
+}
+
+// runAgent serves the node-facing API and moves the node between pending and
+// adopted for as long as the process runs. advertise is added to the TXT
+// records while the node is pending.
+func runAgent(cfg *config.Config, advertise ...string) {
+	nodeID, err := fingerprint.LoadNodeID(cfg.NodeIDPath())
+	if err != nil {
+		log.Panicf("Failed to determine node id: %v", err)
+	}
+	log.Infof("Node ID: %s", nodeID)
 
-		// Start GRPC Server (Listens on all modes)
-		apiPort := proto.FindOpenPort(cfg.DefaultPort)
-		if apiPort == 0 {
-			log.Panic("No open port found")
-		} else if apiPort != cfg.DefaultPort {
-			log.Warnf("Default port %d is in use. Using port %d instead.", cfg.DefaultPort, apiPort)
+	// Verify that the prerequisites are met
+	if !noVerify {
+		if err := k3s.VerifyK3sInstallation("agent"); err != nil {
+			log.Panicf("K3s verification failed: %v", err)
 		}
+	}
 
-		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", apiPort))
+	identity, err := pki.LoadIdentity(cfg.PKIDir(), nodeID)
+	if err != nil {
+		log.Panicf("Failed to load node identity: %v", err)
+	}
+
+	sel, err := newSelector(cfg)
+	if err != nil {
+		log.Panicf("Invalid network config: %v", err)
+	}
+	finder, err := newDiscoverer(cfg, sel, identity)
+	if err != nil {
+		log.Panicf("Invalid discovery config: %v", err)
+	}
+
+	// Start GRPC Server (Listens on all modes)
+	listenHost, err := sel.ListenHost()
+	if err != nil {
+		log.Panicf("Failed to pick listen address: %v", err)
+	}
+	lis, apiPort := proto.ListenOpenPort(listenHost, cfg.DefaultPort)
+	if lis == nil {
+		log.Panic("No open port found")
+	} else if apiPort != cfg.DefaultPort {
+		log.Warnf("Default port %d is in use. Using port %d instead.", cfg.DefaultPort, apiPort)
+	}
+
+	adopted := make(chan struct{}, 1)
+	server := &proto.Server{
+		NodeID:   nodeID,
+		Cluster:  cfg.Cluster.Name,
+		Identity: identity,
+		Net:      sel,
+		OnAdopted: func(req *pb.AdoptRequest) {
+			err := agent.SaveState(cfg.AgentStatePath(), &agent.State{
+				NodeID:         nodeID,
+				ControllerIP:   req.ControllerIp,
+				ControllerPort: int(req.ControllerPort),
+				Role:           req.Role,
+				Cluster:        req.Cluster,
+				AdoptedAt:      time.Now(),
+			})
+			if err != nil {
+				log.Errorf("Failed to persist adoption: %v", err)
+			}
+			select {
+			case adopted <- struct{}{}:
+			default:
+			}
+		},
+	}
+
+	if cfg.Cluster.Name != "" {
+		log.Infof("Pinned to cluster %q.", cfg.Cluster.Name)
+	}
+	advertise = append(advertise, discovery.ClusterRecords(cfg.Cluster.Name)...)
+
+	s := grpc.NewServer(grpc.Creds(credentials.NewTLS(identity.ServerTLSConfig(pki.OrgController))))
+	pb.RegisterFlockServiceServer(s, server)
+	go s.Serve(lis)
+
+	for {
+		state, err := agent.LoadState(cfg.AgentStatePath())
 		if err != nil {
-			log.Panicf("failed to listen: %v", err)
+			log.Panicf("Failed to load agent state: %v", err)
 		}
-		s := grpc.NewServer()
-		pb.RegisterFlockServiceServer(s, &proto.Server{})
-		go s.Serve(lis)
+		if state == nil || !identity.Adopted() {
+			discovery.RunPendingMode(finder, nodeID, uint16(apiPort), adopted, advertise...)
 
-		discovery.RunPendingMode(hostname, uint16(apiPort))
-	},
+			if state, err = agent.LoadState(cfg.AgentStatePath()); err != nil || state == nil {
+				log.Panicf("Adopted but agent state is unavailable: %v", err)
+			}
+		}
+
+		// Returns once the controller asks us to revert to pending
+		discovery.RunComputeMode(finder, nodeID, uint16(apiPort), identity, state, cfg.Heartbeat.Interval)
+
+		if err := agent.Reset(cfg.AgentStatePath(), identity); err != nil {
+			log.Panicf("Failed to reset node: %v", err)
+		}
+	}
 }
 
 func init() {
@@ -1,19 +1,22 @@
 package cmd
 
 import (
-	"fmt"
-	"net"
-	"os"
+	"context"
 
 	"github.com/lunarhue/libs-go/log"
 	"github.com/lunarhue/metallic-flock/pkg/adoption"
 	"github.com/lunarhue/metallic-flock/pkg/config"
 	"github.com/lunarhue/metallic-flock/pkg/discovery"
+	"github.com/lunarhue/metallic-flock/pkg/fingerprint"
+	"github.com/lunarhue/metallic-flock/pkg/inventory"
 	"github.com/lunarhue/metallic-flock/pkg/k3s"
+	"github.com/lunarhue/metallic-flock/pkg/pki"
 	"github.com/lunarhue/metallic-flock/pkg/proto"
 	pb "github.com/lunarhue/metallic-flock/pkg/proto/adoption/v1"
+	pbc "github.com/lunarhue/metallic-flock/pkg/proto/controller/v1"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 var noVerify bool = false
@@ -27,32 +30,103 @@ var controllerCmd = &cobra.Command{
 			log.Panicf("Failed to load config: %v", err)
 		}
 
-		hostname, _ := os.Hostname()
-		// Verify that the prerequisites are met
-		if !noVerify {
-			if err := k3s.VerifyK3sInstallation("server"); err != nil {
-				log.Panicf("K3s verification failed: %v", err)
-			}
-		}
+		runController(cfg)
+	},
+}
 
-		// Start GRPC Server (Listens on all modes)
-		apiPort := proto.FindOpenPort(cfg.DefaultPort)
-		if apiPort == 0 {
-			log.Panic("No open port found")
-		} else if apiPort != cfg.DefaultPort {
-			log.Warnf("Default port %d is in use. Using port %d instead.", cfg.DefaultPort, apiPort)
-		}
+// runController runs the k3s server, adopts discovered nodes and serves the
+// management API until interrupted.
+func runController(cfg *config.Config) {
+	nodeID, err := fingerprint.LoadNodeID(cfg.NodeIDPath())
+	if err != nil {
+		log.Panicf("Failed to determine node id: %v", err)
+	}
+	log.Infof("Node ID: %s", nodeID)
 
-		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", apiPort))
-		if err != nil {
-			log.Panicf("failed to listen: %v", err)
+	// Verify that the prerequisites are met
+	if !noVerify {
+		if err := k3s.VerifyK3sInstallation("server"); err != nil {
+			log.Panicf("K3s verification failed: %v", err)
 		}
-		s := grpc.NewServer()
-		pb.RegisterFlockServiceServer(s, &proto.Server{})
-		go s.Serve(lis)
+	}
 
-		discovery.RunControllerMode(hostname, uint16(apiPort), func(ip, role string) { adoption.AdoptNode(apiPort, proto.CurrentLocalIP(), ip, role) })
-	},
+	policy := adoption.Policy{Mode: cfg.Adoption.Policy, Allowlist: cfg.Adoption.Allowlist}
+	if err := policy.Validate(); err != nil {
+		log.Panicf("Invalid adoption config: %v", err)
+	}
+
+	ca, identity, err := pki.LoadController(cfg.PKIDir(), nodeID)
+	if err != nil {
+		log.Panicf("Failed to load cluster CA: %v", err)
+	}
+
+	sel, err := newSelector(cfg)
+	if err != nil {
+		log.Panicf("Invalid network config: %v", err)
+	}
+	finder, err := newDiscoverer(cfg, sel, identity)
+	if err != nil {
+		log.Panicf("Invalid discovery config: %v", err)
+	}
+
+	store, err := inventory.Open(cfg.InventoryPath())
+	if err != nil {
+		log.Panicf("Failed to open node inventory: %v", err)
+	}
+	defer store.Close()
+
+	// Start GRPC Server (Listens on all modes)
+	listenHost, err := sel.ListenHost()
+	if err != nil {
+		log.Panicf("Failed to pick listen address: %v", err)
+	}
+	lis, apiPort := proto.ListenOpenPort(listenHost, cfg.DefaultPort)
+	if lis == nil {
+		log.Panic("No open port found")
+	} else if apiPort != cfg.DefaultPort {
+		log.Warnf("Default port %d is in use. Using port %d instead.", cfg.DefaultPort, apiPort)
+	}
+
+	adopter := &adoption.Controller{
+		CA:        ca,
+		Identity:  identity,
+		Inventory: store,
+		Policy:    policy,
+		Servers: adoption.ServerPolicy{
+			Count:       cfg.Cluster.Servers,
+			MinCPUs:     cfg.Cluster.ServerMinCPUs,
+			MinMemoryGB: cfg.Cluster.ServerMinMemoryGB,
+		},
+		Retry: adoption.RetryPolicy{
+			MaxAttempts: cfg.Adoption.MaxAttempts,
+			Backoff:     cfg.Adoption.RetryBackoff,
+			MaxBackoff:  cfg.Adoption.MaxRetryBackoff,
+		},
+		Port:    apiPort,
+		Cluster: cfg.Cluster.Name,
+		Net:     sel,
+	}
+
+	s := grpc.NewServer(append(proto.NodeAccess(), grpc.Creds(credentials.NewTLS(identity.ServerTLSConfig(pki.OrgNode, pki.OrgController))))...)
+	pb.RegisterFlockServiceServer(s, &proto.Server{Heartbeats: adopter})
+	go s.Serve(lis)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go adopter.MonitorHeartbeats(ctx, cfg.Heartbeat.GracePeriod)
+	go adopter.RunAdoptions(ctx, cfg.Adoption.Workers)
+
+	// Management API for operators (Local socket only)
+	mgmtLis, err := proto.ListenUnix(cfg.ControllerSocket)
+	if err != nil {
+		log.Panicf("failed to listen on %s: %v", cfg.ControllerSocket, err)
+	}
+	mgmt := grpc.NewServer()
+	pbc.RegisterControllerServiceServer(mgmt, &proto.ControllerServer{Nodes: adopter, Inventory: store})
+	go mgmt.Serve(mgmtLis)
+	defer mgmt.Stop()
+
+	discovery.RunControllerMode(finder, nodeID, uint16(apiPort), cfg.Cluster.Name, adopter.HandleDiscovery)
 }
 
 func init() {
@@ -3,17 +3,33 @@ package debug
 import (
 	"encoding/base64"
 	"encoding/json"
+	"os"
 
 	"github.com/lunarhue/libs-go/log"
 	"github.com/lunarhue/metallic-flock/pkg/fingerprint"
 	"github.com/spf13/cobra"
 )
 
+var snapshotPath, chroot string
+
 var fingerprintCmd = &cobra.Command{
 	Use:   "fingerprint",
 	Short: "Generates a fingerprint.",
-	Long:  `Generates a fingerprint off of hardware specs and logs the JSON and Base64 representation.`,
+	Long: `Generates a fingerprint off of hardware specs and logs the JSON and Base64 representation.
+
+--snapshot and --chroot read the hardware from a ghw snapshot or a copy of
+/sys and /proc instead, e.g. to check the GPU inventory on a machine without
+GPUs. They set GHW_SNAPSHOT_PATH and GHW_CHROOT, which work as well. A
+snapshot's PCI devices are named from the host's PCI ID database unless
+PCIDB_PATH points at another, e.g. pkg/fingerprint/testdata/gpu's.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if snapshotPath != "" {
+			os.Setenv("GHW_SNAPSHOT_PATH", snapshotPath)
+		}
+		if chroot != "" {
+			os.Setenv("GHW_CHROOT", chroot)
+		}
+
 		fingerprint, err := fingerprint.GetFingerprint()
 		if err != nil {
 			log.Panicf("Fingerprint failed: %v", err)
@@ -32,5 +48,7 @@ var fingerprintCmd = &cobra.Command{
 }
 
 func init() {
+	fingerprintCmd.Flags().StringVar(&snapshotPath, "snapshot", "", "Read hardware from this ghw snapshot")
+	fingerprintCmd.Flags().StringVar(&chroot, "chroot", "", "Read hardware from this root directory")
 	RootCmd.AddCommand(fingerprintCmd)
 }
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/lunarhue/metallic-flock/pkg/config"
+	"github.com/lunarhue/metallic-flock/pkg/discovery"
+	"github.com/lunarhue/metallic-flock/pkg/network"
+	"github.com/lunarhue/metallic-flock/pkg/pki"
+)
+
+// newSelector returns the interface and address selection from the config.
+func newSelector(cfg *config.Config) (*network.Selector, error) {
+	sel, err := network.New(cfg.Network.Interfaces, cfg.Network.CIDRs, cfg.Network.PreferFamily)
+	if err != nil {
+		return nil, err
+	}
+	sel.DualStack = cfg.Network.DualStack
+	return sel, nil
+}
+
+// newDiscoverer returns the discovery method selected in the config. identity
+// is used to probe static nodes and may be nil where none are browsed.
+func newDiscoverer(cfg *config.Config, sel *network.Selector, identity *pki.Identity) (discovery.Discoverer, error) {
+	switch cfg.Discovery.Method {
+	case discovery.MethodZeroconf, "":
+		return discovery.Zeroconf{Net: sel}, nil
+	case discovery.MethodStatic:
+		d := &discovery.Static{
+			Nodes:       cfg.Discovery.Static.Nodes,
+			Controllers: cfg.Discovery.Static.Controllers,
+			Cluster:     cfg.Cluster.Name,
+			Interval:    cfg.Discovery.Interval,
+			Net:         sel,
+		}
+		if identity != nil {
+			d.TLS = identity.ProbeTLSConfig()
+		}
+		return d, nil
+	case discovery.MethodDNSSD:
+		return &discovery.DNSSD{
+			Domain:   cfg.Discovery.DNSSD.Domain,
+			Server:   cfg.Discovery.DNSSD.Server,
+			Interval: cfg.Discovery.Interval,
+			Net:      sel,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown discovery method %q (expected %s, %s or %s)",
+			cfg.Discovery.Method, discovery.MethodZeroconf, discovery.MethodStatic, discovery.MethodDNSSD)
+	}
+}
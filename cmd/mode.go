@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/lunarhue/libs-go/log"
+	"github.com/lunarhue/metallic-flock/pkg/agent"
+	"github.com/lunarhue/metallic-flock/pkg/config"
+	"github.com/lunarhue/metallic-flock/pkg/discovery"
+	"github.com/lunarhue/metallic-flock/pkg/fingerprint"
+	"github.com/lunarhue/metallic-flock/pkg/pki"
+)
+
+// Operation modes selected with --mode, METALLIC_FLOCK_MODE or the mode config
+// key. "server" is accepted as an alias for controller.
+const (
+	ModeAgent      = "agent"
+	ModeController = "controller"
+	ModeServer     = "server"
+	ModeAuto       = "auto"
+)
+
+// runMode runs the agent or controller as selected by mode.
+func runMode(cfg *config.Config, mode string) error {
+	var advertise []string
+	if mode == ModeAuto {
+		mode, advertise = resolveAutoMode(cfg)
+	}
+
+	switch mode {
+	case ModeAgent:
+		runAgent(cfg, advertise...)
+	case ModeController, ModeServer:
+		runController(cfg)
+	default:
+		return fmt.Errorf("unknown mode %q (expected %s, %s or %s)", mode, ModeAgent, ModeController, ModeAuto)
+	}
+	return nil
+}
+
+// resolveAutoMode sticks with the role this machine had before. Otherwise it
+// joins the controller it can find or, if there is none, takes part in an
+// election among the auto-mode nodes on the network. It also returns the TXT
+// records an agent should advertise while pending.
+func resolveAutoMode(cfg *config.Config) (string, []string) {
+	if state, err := agent.LoadState(cfg.AgentStatePath()); err == nil && state != nil {
+		log.Infof("Auto mode: adopted by %s before. Running as agent.", state.ControllerIP)
+		return ModeAgent, nil
+	}
+	if pki.HasCA(cfg.PKIDir()) {
+		log.Infof("Auto mode: this machine holds the cluster CA. Running as controller.")
+		return ModeController, nil
+	}
+
+	nodeID, err := fingerprint.LoadNodeID(cfg.NodeIDPath())
+	if err != nil {
+		log.Panicf("Failed to determine node id: %v", err)
+	}
+
+	sel, err := newSelector(cfg)
+	if err != nil {
+		log.Panicf("Invalid network config: %v", err)
+	}
+
+	// Elections need mDNS, but a controller may still be reachable through
+	// the configured discovery method.
+	if cfg.Discovery.Method != discovery.MethodZeroconf {
+		finder, err := newDiscoverer(cfg, sel, nil)
+		if err != nil {
+			log.Panicf("Invalid discovery config: %v", err)
+		}
+		if ip := discovery.ScanForControllers(finder, cfg.Cluster.Name, cfg.Auto.ScanWindow); ip != "" {
+			log.Infof("Auto mode: found controller at %s. Running as agent.", ip)
+			return ModeAgent, nil
+		}
+	}
+
+	result := discovery.Zeroconf{Net: sel}.RunElection(nodeID, cfg.Cluster.Name, uint16(cfg.DefaultPort), cfg.Auto.ScanWindow, cfg.Auto.ScanJitter)
+	switch {
+	case result.ControllerIP != "":
+		log.Infof("Auto mode: found controller at %s. Running as agent.", result.ControllerIP)
+		return ModeAgent, nil
+	case result.Controller:
+		log.Infof("Auto mode: elected as controller.")
+		return ModeController, nil
+	default:
+		log.Infof("Auto mode: %s was elected as controller. Running as agent.", result.Winner)
+		return ModeAgent, discovery.ElectedRecords(result.Winner)
+	}
+}
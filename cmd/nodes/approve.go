@@ -0,0 +1,30 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+
+	pbc "github.com/lunarhue/metallic-flock/pkg/proto/controller/v1"
+	"github.com/spf13/cobra"
+)
+
+var approveCmd = &cobra.Command{
+	Use:   "approve <id>",
+	Short: "Approves a pending node for adoption.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withClient(cmd, func(ctx context.Context, client pbc.ControllerServiceClient) error {
+			resp, err := client.ApproveNode(ctx, &pbc.ApproveNodeRequest{NodeId: args[0]})
+			if err != nil {
+				return fmt.Errorf("failed to approve %s: %w", args[0], err)
+			}
+
+			fmt.Printf("Node %s approved (state: %s)\n", args[0], resp.State)
+			return nil
+		})
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(approveCmd)
+}
@@ -0,0 +1,53 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	pbc "github.com/lunarhue/metallic-flock/pkg/proto/controller/v1"
+	"github.com/spf13/cobra"
+)
+
+var listState string
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the nodes known to the controller.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withClient(cmd, func(ctx context.Context, client pbc.ControllerServiceClient) error {
+			resp, err := client.ListNodes(ctx, &pbc.ListNodesRequest{State: listState})
+			if err != nil {
+				return fmt.Errorf("failed to list nodes: %w", err)
+			}
+
+			views := make([]nodeView, 0, len(resp.Nodes))
+			for _, node := range resp.Nodes {
+				views = append(views, toView(node))
+			}
+			if done, err := printStructured(os.Stdout, views); done {
+				return err
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tHOSTNAME\tIP\tROLE\tSTATE\tPRESENT\tLAST SEEN")
+			for _, node := range resp.Nodes {
+				state := node.State
+				if node.AdoptionPhase != "" && node.State == "adopting" {
+					state += " (" + node.AdoptionPhase + ")"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%t\t%s\n",
+					node.Id, orDash(node.Hostname), orDash(node.Ip), orDash(node.Role), state, node.Present, ago(node.LastSeen))
+			}
+			return w.Flush()
+		})
+	},
+}
+
+func init() {
+	listCmd.Flags().StringVar(&listState, "state", "", "Only list nodes in this state, e.g. awaiting_approval")
+	addOutputFlag(listCmd)
+	RootCmd.AddCommand(listCmd)
+}
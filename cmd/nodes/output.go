@@ -0,0 +1,209 @@
+package nodes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	pbc "github.com/lunarhue/metallic-flock/pkg/proto/controller/v1"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats for list and show.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+var outputFormat string
+
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", outputTable, "Output format (table, json, yaml)")
+}
+
+// nodeView is how a node is printed as JSON or YAML: with readable times and
+// the fingerprint inlined rather than as encoded bytes.
+type nodeView struct {
+	ID                string            `json:"id"`
+	Hostname          string            `json:"hostname,omitempty"`
+	IP                string            `json:"ip,omitempty"`
+	Port              uint32            `json:"port,omitempty"`
+	Role              string            `json:"role,omitempty"`
+	State             string            `json:"state"`
+	Approved          bool              `json:"approved"`
+	Present           bool              `json:"present"`
+	AdoptionPhase     string            `json:"adoption_phase,omitempty"`
+	LastError         string            `json:"last_error,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+	Status            *statusView       `json:"status,omitempty"`
+	Attempts          []attemptView     `json:"attempts,omitempty"`
+	FirstSeen         *time.Time        `json:"first_seen,omitempty"`
+	LastSeen          *time.Time        `json:"last_seen,omitempty"`
+	AdoptionStartedAt *time.Time        `json:"adoption_started_at,omitempty"`
+	AdoptedAt         *time.Time        `json:"adopted_at,omitempty"`
+	LastHeartbeat     *time.Time        `json:"last_heartbeat,omitempty"`
+	NextAttemptAt     *time.Time        `json:"next_attempt_at,omitempty"`
+	StateChangedAt    *time.Time        `json:"state_changed_at,omitempty"`
+	UpdatedAt         *time.Time        `json:"updated_at,omitempty"`
+	PresenceChangedAt *time.Time        `json:"presence_changed_at,omitempty"`
+	Fingerprint       json.RawMessage   `json:"fingerprint,omitempty"`
+}
+
+type statusView struct {
+	K3sUnit              string  `json:"k3s_unit"`
+	Load1                float64 `json:"load1"`
+	Load5                float64 `json:"load5"`
+	Load15               float64 `json:"load15"`
+	MemoryTotalBytes     uint64  `json:"memory_total_bytes"`
+	MemoryAvailableBytes uint64  `json:"memory_available_bytes"`
+}
+
+type attemptView struct {
+	Role       string     `json:"role"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+func toView(node *pbc.Node) nodeView {
+	view := nodeView{
+		ID:                node.Id,
+		Hostname:          node.Hostname,
+		IP:                node.Ip,
+		Port:              node.Port,
+		Role:              node.Role,
+		State:             node.State,
+		Approved:          node.Approved,
+		Present:           node.Present,
+		AdoptionPhase:     node.AdoptionPhase,
+		LastError:         node.LastError,
+		Metadata:          node.Metadata,
+		FirstSeen:         fromUnix(node.FirstSeen),
+		LastSeen:          fromUnix(node.LastSeen),
+		AdoptionStartedAt: fromUnix(node.AdoptionStartedAt),
+		AdoptedAt:         fromUnix(node.AdoptedAt),
+		LastHeartbeat:     fromUnix(node.LastHeartbeat),
+		NextAttemptAt:     fromUnix(node.NextAttemptAt),
+		StateChangedAt:    fromUnix(node.StateChangedAt),
+		UpdatedAt:         fromUnix(node.UpdatedAt),
+		PresenceChangedAt: fromUnix(node.PresenceChangedAt),
+	}
+
+	if s := node.Status; s != nil {
+		view.Status = &statusView{
+			K3sUnit:              s.K3SUnit,
+			Load1:                s.Load1,
+			Load5:                s.Load5,
+			Load15:               s.Load15,
+			MemoryTotalBytes:     s.MemoryTotalBytes,
+			MemoryAvailableBytes: s.MemoryAvailableBytes,
+		}
+	}
+
+	for _, a := range node.Attempts {
+		view.Attempts = append(view.Attempts, attemptView{
+			Role:       a.Role,
+			StartedAt:  fromUnix(a.StartedAt),
+			FinishedAt: fromUnix(a.FinishedAt),
+			Error:      a.Error,
+		})
+	}
+
+	if len(node.Fingerprint) > 0 {
+		view.Fingerprint = json.RawMessage(node.Fingerprint)
+	}
+
+	return view
+}
+
+// printStructured writes v as JSON or YAML. It reports false for the table
+// format, which every command renders itself.
+func printStructured(w io.Writer, v any) (bool, error) {
+	switch outputFormat {
+	case outputTable:
+		return false, nil
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return true, enc.Encode(v)
+	case outputYAML:
+		data, err := toYAML(v)
+		if err != nil {
+			return true, err
+		}
+		_, err = w.Write(data)
+		return true, err
+	default:
+		return true, fmt.Errorf("unknown output format %q (expected %s, %s or %s)", outputFormat, outputTable, outputJSON, outputYAML)
+	}
+}
+
+// toYAML goes through JSON so the field names match the JSON output, the
+// fingerprint types only carry json tags. Decoding into a yaml.Node keeps the
+// field order.
+func toYAML(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	resetStyle(&doc)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resetStyle turns JSON's flow style into block style.
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+func fromUnix(seconds int64) *time.Time {
+	if seconds == 0 {
+		return nil
+	}
+	t := time.Unix(seconds, 0)
+	return &t
+}
+
+// ago renders a Unix time relative to now, e.g. "3m ago".
+func ago(seconds int64) string {
+	if seconds == 0 {
+		return "-"
+	}
+
+	d := time.Since(time.Unix(seconds, 0)).Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds ago", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,30 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+
+	pbc "github.com/lunarhue/metallic-flock/pkg/proto/controller/v1"
+	"github.com/spf13/cobra"
+)
+
+var rejectCmd = &cobra.Command{
+	Use:   "reject <id>",
+	Short: "Rejects a pending node so it is never adopted.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withClient(cmd, func(ctx context.Context, client pbc.ControllerServiceClient) error {
+			resp, err := client.RejectNode(ctx, &pbc.RejectNodeRequest{NodeId: args[0]})
+			if err != nil {
+				return fmt.Errorf("failed to reject %s: %w", args[0], err)
+			}
+
+			fmt.Printf("Node %s rejected (state: %s)\n", args[0], resp.State)
+			return nil
+		})
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(rejectCmd)
+}
@@ -0,0 +1,36 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+
+	pbc "github.com/lunarhue/metallic-flock/pkg/proto/controller/v1"
+	"github.com/spf13/cobra"
+)
+
+var removeForce bool
+
+var removeCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Removes a node from the controller's inventory.",
+	Long: `Makes the controller forget a node. Nodes that are part of the cluster are only
+removed with --force, which also deletes them from Kubernetes. A node that is
+still advertising itself will be discovered again.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withClient(cmd, func(ctx context.Context, client pbc.ControllerServiceClient) error {
+			_, err := client.RemoveNode(ctx, &pbc.RemoveNodeRequest{NodeId: args[0], Force: removeForce})
+			if err != nil {
+				return fmt.Errorf("failed to remove %s: %w", args[0], err)
+			}
+
+			fmt.Printf("Node %s removed\n", args[0])
+			return nil
+		})
+	},
+}
+
+func init() {
+	removeCmd.Flags().BoolVar(&removeForce, "force", false, "Remove the node even if it is part of the cluster")
+	RootCmd.AddCommand(removeCmd)
+}
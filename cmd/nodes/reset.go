@@ -0,0 +1,31 @@
+package nodes
+
+import (
+	"context"
+	"fmt"
+
+	pbc "github.com/lunarhue/metallic-flock/pkg/proto/controller/v1"
+	"github.com/spf13/cobra"
+)
+
+var resetCmd = &cobra.Command{
+	Use:   "reset <id>",
+	Short: "Sends an adopted node back to pending so it can be adopted again.",
+	Long:  `Asks the node to stop its k3s agent, wipe its cluster state and revert to pending on its next heartbeat.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withClient(cmd, func(ctx context.Context, client pbc.ControllerServiceClient) error {
+			resp, err := client.ResetNode(ctx, &pbc.ResetNodeRequest{NodeId: args[0]})
+			if err != nil {
+				return fmt.Errorf("failed to reset %s: %w", args[0], err)
+			}
+
+			fmt.Printf("Node %s will revert to pending on its next heartbeat (state: %s)\n", args[0], resp.State)
+			return nil
+		})
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(resetCmd)
+}
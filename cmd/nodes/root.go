@@ -0,0 +1,51 @@
+package nodes
+
+import (
+	"context"
+	"time"
+
+	"github.com/lunarhue/metallic-flock/pkg/config"
+	"github.com/lunarhue/metallic-flock/pkg/proto"
+	pbc "github.com/lunarhue/metallic-flock/pkg/proto/controller/v1"
+	"github.com/spf13/cobra"
+)
+
+const defaultSocket = "/run/metallic-flock/controller.sock"
+
+var socketPath string
+
+var RootCmd = &cobra.Command{
+	Use:   "nodes",
+	Short: "Inspect and manage the nodes known to the running controller.",
+	// Once the arguments parsed, errors come from the controller rather than
+	// from how the command was invoked, so don't follow them with the usage.
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cmd.SilenceUsage = true
+	},
+}
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&socketPath, "socket", defaultSocket, "Controller management socket (defaults to controller_socket from config)")
+}
+
+// withClient connects to the controller's management socket and runs fn with
+// a bounded context.
+func withClient(cmd *cobra.Command, fn func(ctx context.Context, client pbc.ControllerServiceClient) error) error {
+	path := socketPath
+	if !cmd.Flags().Changed("socket") {
+		if cfg, err := config.Load(); err == nil && cfg.ControllerSocket != "" {
+			path = cfg.ControllerSocket
+		}
+	}
+
+	client, conn, err := proto.DialController(path)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+	defer cancel()
+
+	return fn(ctx, client)
+}
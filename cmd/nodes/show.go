@@ -0,0 +1,231 @@
+package nodes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lunarhue/metallic-flock/pkg/fingerprint"
+	pbc "github.com/lunarhue/metallic-flock/pkg/proto/controller/v1"
+	"github.com/spf13/cobra"
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Shows everything the controller knows about a node.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withClient(cmd, func(ctx context.Context, client pbc.ControllerServiceClient) error {
+			resp, err := client.GetNode(ctx, &pbc.GetNodeRequest{NodeId: args[0]})
+			if err != nil {
+				return fmt.Errorf("failed to get %s: %w", args[0], err)
+			}
+
+			if done, err := printStructured(os.Stdout, toView(resp.Node)); done {
+				return err
+			}
+
+			return printNode(os.Stdout, resp.Node)
+		})
+	},
+}
+
+func printNode(out io.Writer, node *pbc.Node) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	field := func(name, value string) {
+		fmt.Fprintf(w, "%s:\t%s\n", name, value)
+	}
+
+	field("ID", node.Id)
+	field("Hostname", orDash(node.Hostname))
+	address := "-"
+	if node.Ip != "" {
+		address = net.JoinHostPort(node.Ip, strconv.Itoa(int(node.Port)))
+	}
+	field("Address", address)
+	field("Role", orDash(node.Role))
+	field("State", node.State)
+	if node.AdoptionPhase != "" {
+		field("Adoption Phase", node.AdoptionPhase)
+	}
+	field("Approved", fmt.Sprintf("%t", node.Approved))
+	present := fmt.Sprintf("%t", node.Present)
+	if node.PresenceChangedAt != 0 {
+		present += ", since " + timestamp(node.PresenceChangedAt)
+	}
+	field("Present", present)
+	if node.LastError != "" {
+		field("Last Error", node.LastError)
+	}
+	field("First Seen", timestamp(node.FirstSeen))
+	field("Last Seen", timestamp(node.LastSeen))
+	if node.AdoptedAt != 0 {
+		field("Adopted", timestamp(node.AdoptedAt))
+	}
+	if node.LastHeartbeat != 0 {
+		field("Last Heartbeat", timestamp(node.LastHeartbeat))
+	}
+	if node.NextAttemptAt != 0 {
+		field("Next Attempt", timestamp(node.NextAttemptAt))
+	}
+
+	if s := node.Status; s != nil {
+		field("K3s Unit", s.K3SUnit)
+		field("Load", fmt.Sprintf("%.2f %.2f %.2f", s.Load1, s.Load5, s.Load15))
+		field("Memory", fmt.Sprintf("%s available of %s", bytesGB(s.MemoryAvailableBytes), bytesGB(s.MemoryTotalBytes)))
+	}
+
+	if len(node.Fingerprint) > 0 {
+		var fp fingerprint.Fingerprint
+		if err := json.Unmarshal(node.Fingerprint, &fp); err != nil {
+			field("Hardware", "unreadable fingerprint: "+err.Error())
+		} else {
+			printHardware(field, &fp)
+		}
+	}
+
+	if len(node.Metadata) > 0 {
+		keys := make([]string, 0, len(node.Metadata))
+		for key := range node.Metadata {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		fmt.Fprintln(w, "Metadata:\t")
+		for _, key := range keys {
+			fmt.Fprintf(w, "  %s:\t%s\n", key, node.Metadata[key])
+		}
+	}
+
+	if len(node.Attempts) > 0 {
+		fmt.Fprintln(w, "Attempts:\t")
+		for _, a := range node.Attempts {
+			outcome := "ok"
+			if a.Error != "" {
+				outcome = a.Error
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", timestamp(a.StartedAt), a.Role, outcome)
+		}
+	}
+
+	return w.Flush()
+}
+
+func printHardware(field func(name, value string), fp *fingerprint.Fingerprint) {
+	field("Arch", fp.System.Arch)
+	field("TPM", fp.System.TpmVersion)
+
+	dmi := fp.System.Dmi
+	if dmi.Vendor != "" || dmi.Product != "" {
+		field("System", strings.TrimSpace(dmi.Vendor+" "+dmi.Product))
+	}
+	if dmi.SerialNumber != "" {
+		field("Serial", dmi.SerialNumber)
+	}
+	if dmi.UUID != "" {
+		field("SMBIOS UUID", dmi.UUID)
+	}
+	if dmi.BoardVendor != "" || dmi.BoardProduct != "" {
+		field("Board", strings.TrimSpace(dmi.BoardVendor+" "+dmi.BoardProduct))
+	}
+	if dmi.ChassisAssetTag != "" {
+		field("Asset Tag", dmi.ChassisAssetTag)
+	}
+	if dmi.BiosVersion != "" {
+		field("BIOS", fmt.Sprintf("%s %s (%s)", dmi.BiosVendor, dmi.BiosVersion, orDash(dmi.BiosDate)))
+	}
+
+	for _, cpu := range fp.Cpus {
+		field("CPU", fmt.Sprintf("%s (%d cores, %d threads)", cpu.Model, cpu.Cores, cpu.Threads))
+	}
+	field("Memory Total", bytesGB(uint64(fp.Memory.TotalBytes)))
+	for _, m := range fp.Memory.Modules {
+		if m.SizeBytes == 0 {
+			field("DIMM", fmt.Sprintf("%s empty", m.Locator))
+			continue
+		}
+		field("DIMM", fmt.Sprintf("%s %s %s %d MT/s %s", m.Locator, bytesGB(uint64(m.SizeBytes)), orDash(m.Type), m.SpeedMTs, m.Manufacturer))
+	}
+	for _, hp := range fp.Memory.HugePages {
+		if hp.Total > 0 {
+			field("Huge Pages", fmt.Sprintf("%d x %d kB (%d free)", hp.Total, hp.SizeBytes/1024, hp.Free))
+		}
+	}
+	if len(fp.Numa) > 1 {
+		for _, n := range fp.Numa {
+			field("NUMA Node", fmt.Sprintf("%d: %d CPUs, %s", n.ID, len(n.Cpus), bytesGB(uint64(n.MemoryBytes))))
+		}
+	}
+
+	for _, nic := range fp.Network {
+		ips := orDash(nic.CurrentIp)
+		if nic.CurrentIpv6 != "" {
+			ips += " " + nic.CurrentIpv6
+		}
+		field("NIC", fmt.Sprintf("%s %s %s %s", nic.InterfaceName, nic.MacAddress, ips, nic.Vendor))
+
+		link := orDash(nic.LinkState)
+		if nic.SpeedMbps > 0 {
+			link += fmt.Sprintf(" %d Mb/s %s", nic.SpeedMbps, nic.Duplex)
+		}
+		link += fmt.Sprintf(" MTU %d, %s", nic.Mtu, orDash(nic.Driver))
+		if nic.Master != "" {
+			link += fmt.Sprintf(", %s %s", nic.MasterType, nic.Master)
+		}
+		if len(nic.Vlans) > 0 {
+			link += ", VLANs " + strings.Join(nic.Vlans, " ")
+		}
+		if nic.SriovTotalVfs > 0 {
+			link += fmt.Sprintf(", SR-IOV %d/%d VFs", nic.SriovNumVfs, nic.SriovTotalVfs)
+		}
+		field("  Link", link)
+	}
+	for _, disk := range fp.Storage {
+		kind := "SSD"
+		if disk.Rotational {
+			kind = "HDD"
+		}
+		health := "-"
+		if h := disk.Health; h != nil {
+			health = "SMART FAILED"
+			if h.Passed {
+				health = "SMART ok"
+			}
+		}
+		field("Disk", fmt.Sprintf("%s %s %s %s/%s %s %s", disk.DeviceName, bytesGB(uint64(disk.SizeBytes)), disk.Model, orDash(disk.Bus), kind, orDash(disk.Serial), health))
+		for _, part := range disk.Partitions {
+			field("  Partition", fmt.Sprintf("%s %s %s %s", part.Name, bytesGB(uint64(part.SizeBytes)), orDash(part.Filesystem), orDash(part.MountPoint)))
+		}
+	}
+	for _, gpu := range fp.Gpus {
+		vram := "-"
+		if gpu.VramBytes > 0 {
+			vram = bytesGB(uint64(gpu.VramBytes))
+		}
+		field("GPU", fmt.Sprintf("%s %s %s %s (%s, %s)", gpu.PciAddress, gpu.Vendor, gpu.Model, vram, gpu.Kind, orDash(gpu.Driver)))
+	}
+}
+
+func timestamp(seconds int64) string {
+	if seconds == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%s (%s)", time.Unix(seconds, 0).Format(time.RFC3339), ago(seconds))
+}
+
+func bytesGB(b uint64) string {
+	return fmt.Sprintf("%.1f GB", float64(b)/1024/1024/1024)
+}
+
+func init() {
+	addOutputFlag(showCmd)
+	RootCmd.AddCommand(showCmd)
+}
@@ -4,14 +4,40 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/lunarhue/libs-go/log"
 	"github.com/lunarhue/metallic-flock/cmd/debug"
+	"github.com/lunarhue/metallic-flock/cmd/nodes"
+	"github.com/lunarhue/metallic-flock/pkg/config"
 	"github.com/spf13/cobra"
 )
 
+var mode string
+
 var rootCmd = &cobra.Command{
 	Use:   "metallic",
 	Short: "Used to create base k3s cluster.",
-	Long:  `Sets up the controller and agent relationship and does cluster authentication.`,
+	Long: `Sets up the controller and agent relationship and does cluster authentication.
+
+Without a subcommand it runs in the mode given by --mode, METALLIC_FLOCK_MODE
+or the mode config key: agent, controller (or server) or auto. Auto mode joins
+the controller it finds on the network and becomes the controller otherwise.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			log.Panicf("Failed to load config: %v", err)
+		}
+
+		// The flag wins over the environment and the config file, which
+		// config.Load already merged.
+		if cmd.Flags().Changed("mode") {
+			cfg.Mode = mode
+		}
+
+		if err := runMode(cfg, cfg.Mode); err != nil {
+			log.Panicf("Failed to start: %v", err)
+		}
+	},
 }
 
 func Execute() {
@@ -22,5 +48,9 @@ func Execute() {
 }
 
 func init() {
+	rootCmd.Flags().StringVar(&mode, "mode", "", "Operation mode: agent, controller (or server) or auto (defaults to mode from config)")
+	rootCmd.Flags().BoolVar(&noVerify, "no-verify", false, "Skip K3s installation verification")
+
 	rootCmd.AddCommand(debug.RootCmd)
+	rootCmd.AddCommand(nodes.RootCmd)
 }
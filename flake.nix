@@ -74,7 +74,7 @@
               mode = mkOption {
                 type = types.str;
                 default = "agent";
-                description = "Mode in which to run Compute Flock (agent/controller).";
+                description = "Mode in which to run Compute Flock (agent/controller/auto).";
               };
             };
 
@@ -125,6 +125,7 @@
                   
                   StateDirectory = "metallic-flock";
                   CacheDirectory = "metallic-flock";
+                  RuntimeDirectory = "metallic-flock"; # controller management socket
                 };
               };
             };
@@ -2,45 +2,225 @@ package adoption
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
+	"net"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/lunarhue/libs-go/log"
 
+	"github.com/lunarhue/metallic-flock/pkg/fingerprint"
 	"github.com/lunarhue/metallic-flock/pkg/k3s"
+	"github.com/lunarhue/metallic-flock/pkg/pki"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
 
 	pb "github.com/lunarhue/metallic-flock/pkg/proto/adoption/v1"
 )
 
-func AdoptNode(listenPort int, controllerIp, computeIp string, role string) {
-	conn, err := grpc.NewClient(fmt.Sprintf("%s:%d", computeIp, listenPort), grpc.WithTransportCredentials(insecure.NewCredentials()))
+// joinTimeout bounds how long Adopt waits for the node to become Ready. It
+// is a little longer than the node's own deadline so its error comes through.
+const joinTimeout = 6 * time.Minute
+
+// PendingNode is a connection to a node that has not been issued a
+// certificate yet, pinned to the self-signed certificate the node presented on
+// the first handshake. It is the only connection made to a node without
+// verifying it against the cluster CA; once adopted, the node is only reached
+// through dialNode.
+type PendingNode struct {
+	identity *pki.Identity
+	addr     string
+	ip       string
+	conn     *grpc.ClientConn
+	cert     *x509.Certificate
+}
+
+// ConnectPending learns the node's public key from its self-signed
+// certificate and pins a connection to exactly that key.
+func ConnectPending(identity *pki.Identity, computeIp string, listenPort int) (*PendingNode, error) {
+	addr := net.JoinHostPort(computeIp, strconv.Itoa(listenPort))
+
+	nodeCert, err := probeCertificate(addr, identity.ProbeTLSConfig())
 	if err != nil {
-		log.Errorf("Failed to connect to %s: %v", computeIp, err)
-		return
+		return nil, fmt.Errorf("failed to fetch certificate from %s: %w", computeIp, err)
 	}
 
-	defer conn.Close()
+	creds := credentials.NewTLS(identity.PinnedClientTLSConfig(nodeCert.Raw))
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", computeIp, err)
+	}
+
+	return &PendingNode{identity: identity, addr: addr, ip: computeIp, conn: conn, cert: nodeCert}, nil
+}
+
+func (n *PendingNode) Close() error {
+	return n.conn.Close()
+}
+
+// Fingerprint asks the node for its hardware fingerprint.
+func (n *PendingNode) Fingerprint() (*fingerprint.Fingerprint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := pb.NewFlockServiceClient(n.conn)
+	resp, err := client.GetFingerprint(ctx, &pb.GetFingerprintRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fingerprint from %s: %w", n.ip, err)
+	}
+
+	var fp fingerprint.Fingerprint
+	if err := json.Unmarshal(resp.Fingerprint, &fp); err != nil {
+		return nil, fmt.Errorf("failed to decode fingerprint from %s: %w", n.ip, err)
+	}
 
-	adoptionToken, err := k3s.CreateJoinToken("metallic-flock-node", 1*time.Minute)
+	return &fp, nil
+}
+
+// Adopt issues a certificate for the node's pinned key and hands it over with
+// a join token, then follows the node until it is Ready or the join failed.
+// progress is called with every phase the node reports. cluster is the name
+// of the controller's cluster.
+func (n *PendingNode) Adopt(ca *pki.CA, controllerIp string, controllerPort int, cluster, nodeID, role string, progress func(pb.AdoptionPhase)) error {
+	issued, err := ca.IssueNodeCertificate(nodeID, n.cert.PublicKey, []net.IP{net.ParseIP(n.ip)})
 	if err != nil {
-		log.Errorf("Failed to create join token: %v", err)
-		return
+		return fmt.Errorf("failed to issue certificate for %s: %w", n.ip, err)
 	}
 
-	log.Infof("Generated join token for %s: %s", computeIp, adoptionToken)
+	adoptionToken, err := joinToken(role)
+	if err != nil {
+		return fmt.Errorf("failed to create join token: %w", err)
+	}
+
+	log.Infof("Generated join token for %s: %s", n.ip, k3s.RedactToken(adoptionToken))
+
+	ctx, cancel := context.WithTimeout(context.Background(), joinTimeout)
+	defer cancel()
 
-	client := pb.NewFlockServiceClient(conn)
-	_, err = client.Adopt(context.Background(), &pb.AdoptRequest{
-		ClusterToken: adoptionToken,
-		ControllerIp: controllerIp,
-		Role:         role,
+	client := pb.NewFlockServiceClient(n.conn)
+	resp, err := client.Adopt(ctx, &pb.AdoptRequest{
+		ClusterToken:    adoptionToken,
+		ControllerIp:    controllerIp,
+		ControllerPort:  uint32(controllerPort),
+		Role:            role,
+		Cluster:         cluster,
+		CaCertificate:   ca.CertPEM,
+		NodeCertificate: issued,
 	})
 
 	if err != nil {
-		log.Errorf("Failed to adopt %s: %v", computeIp, err)
-	} else {
-		log.Infof("Successfully sent adoption command to %s", computeIp)
+		return fmt.Errorf("failed to adopt %s: %w", n.ip, err)
+	}
+
+	log.Infof("Sent adoption command to %s (operation %s)", n.ip, resp.OperationId)
+	return watchAdoption(ctx, client, n.identity, n.addr, nodeID, resp.OperationId, progress)
+}
+
+// watchAdoption follows the adoption until it is ready or failed. The node
+// switches to its CA-issued certificate during the adoption, so a dropped
+// stream is resumed over a fresh connection that verifies it.
+func watchAdoption(ctx context.Context, client pb.FlockServiceClient, identity *pki.Identity, addr, nodeID, operationID string, progress func(pb.AdoptionPhase)) error {
+	last := pb.AdoptionPhase_ADOPTION_PHASE_UNSPECIFIED
+
+	var resumed *grpc.ClientConn
+	defer func() {
+		if resumed != nil {
+			resumed.Close()
+		}
+	}()
+
+	for {
+		stream, err := client.WatchAdoption(ctx, &pb.WatchAdoptionRequest{OperationId: operationID})
+		for err == nil {
+			var update *pb.AdoptionStatus
+			if update, err = stream.Recv(); err != nil {
+				break
+			}
+
+			if update.Phase != last {
+				last = update.Phase
+				log.Infof("Adoption of %s: %s", addr, phaseName(last))
+				if progress != nil {
+					progress(last)
+				}
+			}
+
+			switch update.Phase {
+			case pb.AdoptionPhase_ADOPTION_PHASE_READY:
+				return nil
+			case pb.AdoptionPhase_ADOPTION_PHASE_FAILED:
+				return fmt.Errorf("node failed to join: %s", update.Error)
+			}
+		}
+
+		if status.Code(err) == codes.NotFound {
+			return fmt.Errorf("node lost track of adoption %s, it probably restarted", operationID)
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("timed out waiting for node to join (last phase %s)", phaseName(last))
+		}
+
+		log.Warnf("Lost adoption progress stream from %s, reconnecting: %v", addr, err)
+		time.Sleep(2 * time.Second)
+
+		conn, dialErr := dialNode(identity, addr, nodeID)
+		if dialErr != nil {
+			log.Warnf("Failed to reconnect to %s: %v", addr, dialErr)
+			continue
+		}
+		if resumed != nil {
+			resumed.Close()
+		}
+		resumed = conn
+		client = pb.NewFlockServiceClient(conn)
+	}
+}
+
+// phaseName turns ADOPTION_PHASE_UNIT_SPAWNED into unit_spawned.
+func phaseName(phase pb.AdoptionPhase) string {
+	return strings.ToLower(strings.TrimPrefix(phase.String(), "ADOPTION_PHASE_"))
+}
+
+// dialNode connects to the FlockService of an adopted node at addr. The node
+// must present a certificate the cluster CA issued for nodeID.
+func dialNode(identity *pki.Identity, addr, nodeID string) (*grpc.ClientConn, error) {
+	tlsConfig, err := identity.ClientTLSConfig(nodeID)
+	if err != nil {
+		return nil, err
 	}
+
+	return grpc.NewClient(addr, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+}
+
+// joinToken returns a short-lived bootstrap token for agents. Servers need the
+// controller's server token to join its embedded etcd.
+func joinToken(role string) (string, error) {
+	if role == k3s.RoleServer {
+		return k3s.ReadServerToken()
+	}
+	return k3s.CreateJoinToken("metallic-flock-node", 1*time.Minute)
+}
+
+// probeCertificate performs a bare TLS handshake with addr and returns the
+// certificate the peer presented.
+func probeCertificate(addr string, cfg *tls.Config) (*x509.Certificate, error) {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	conn, err := tls.DialWithDialer(dialer, "tcp", addr, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("peer presented no certificate")
+	}
+
+	return certs[0], nil
 }
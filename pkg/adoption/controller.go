@@ -0,0 +1,350 @@
+package adoption
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lunarhue/libs-go/log"
+	"github.com/lunarhue/metallic-flock/pkg/discovery"
+	"github.com/lunarhue/metallic-flock/pkg/inventory"
+	"github.com/lunarhue/metallic-flock/pkg/k3s"
+	"github.com/lunarhue/metallic-flock/pkg/network"
+	"github.com/lunarhue/metallic-flock/pkg/pki"
+	pb "github.com/lunarhue/metallic-flock/pkg/proto/adoption/v1"
+)
+
+// Controller records every candidate found by discovery in the inventory and
+// queues the ones that aren't part of the cluster yet and are admitted by the
+// adoption policy. The rest wait for an operator to approve or reject them.
+// RunAdoptions works through the queue.
+type Controller struct {
+	CA        *pki.CA
+	Identity  *pki.Identity
+	Inventory *inventory.Store
+	Policy    Policy
+	Servers   ServerPolicy
+	Retry     RetryPolicy
+
+	// Port is where the controller's FlockService listens. Adopted nodes
+	// send their heartbeats to it.
+	Port int
+
+	// Cluster is the name of the cluster, handed to nodes on adoption.
+	Cluster string
+
+	// Net picks the controller address handed to each node.
+	Net *network.Selector
+
+	queue  queue
+	roleMu sync.Mutex
+}
+
+// HandleCandidate records a pending node found by discovery and queues it for
+// adoption if the policy admits it.
+func (c *Controller) HandleCandidate(candidate discovery.Candidate) {
+	var moved bool
+	node, err := c.Inventory.Update(candidate.Name, func(n *inventory.Node) error {
+		// Anyone can advertise under a member's name. Its address is
+		// known from adoption and only changes through a reset.
+		if advertisesMember(n.State) {
+			moved = n.IP != candidate.IP || n.Port != candidate.Port
+			n.LastSeen = time.Now()
+			n.SetPresent(true)
+			return nil
+		}
+
+		n.Hostname = candidate.Hostname
+		n.IP = candidate.IP
+		n.Port = candidate.Port
+		n.Metadata = candidate.Metadata
+		n.LastSeen = time.Now()
+		n.SetPresent(true)
+		if n.State == "" {
+			n.SetState(inventory.StateDiscovered)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Errorf("Failed to record %s in inventory: %v", candidate.Name, err)
+		return
+	}
+
+	if moved {
+		log.Warnf("Node %s (%s) is advertised at %s, but is known at %s. Ignoring the new address.",
+			node.ID, node.State, net.JoinHostPort(candidate.IP, strconv.Itoa(int(candidate.Port))), net.JoinHostPort(node.IP, strconv.Itoa(int(node.Port))))
+	}
+
+	switch node.State {
+	case inventory.StateAdopted:
+		log.Infof("Node %s is already adopted. Skipping.", node.ID)
+		return
+	case inventory.StateLost, inventory.StateResetting:
+		log.Debugf("Node %s is %s. Not adopting it again.", node.ID, node.State)
+		return
+	case inventory.StateRejected:
+		log.Debugf("Node %s was rejected. Ignoring.", node.ID)
+		return
+	}
+
+	if !c.Policy.Admits(node) {
+		if node.State != inventory.StateAwaitingApproval {
+			c.update(node.ID, func(n *inventory.Node) {
+				n.SetState(inventory.StateAwaitingApproval)
+			})
+		}
+		log.Infof("Node %s is awaiting approval. Run 'metallic nodes approve %s' to adopt it.", node.ID, node.ID)
+		return
+	}
+
+	c.enqueue(node.ID)
+}
+
+// Approve lets a node through the adoption policy and adopts it right away.
+func (c *Controller) Approve(id string) (*inventory.Node, error) {
+	node, err := c.Inventory.UpdateExisting(id, func(n *inventory.Node) error {
+		if isMember(n.State) {
+			return fmt.Errorf("node %s is already %s", id, n.State)
+		}
+		n.Approved = true
+		n.SetState(inventory.StateDiscovered)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Node %s approved by operator.", id)
+	c.enqueue(node.ID)
+
+	return node, nil
+}
+
+// Reject marks a node so it is never adopted, even if it keeps advertising.
+func (c *Controller) Reject(id string) (*inventory.Node, error) {
+	node, err := c.Inventory.UpdateExisting(id, func(n *inventory.Node) error {
+		if isMember(n.State) {
+			return fmt.Errorf("node %s is already %s", id, n.State)
+		}
+		n.Approved = false
+		n.SetState(inventory.StateRejected)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Node %s rejected by operator.", id)
+	return node, nil
+}
+
+// Reset asks an adopted node to tear down its k3s agent and revert to
+// pending. The node picks the request up with its next heartbeat, after which
+// it has to pass the adoption policy again.
+func (c *Controller) Reset(id string) (*inventory.Node, error) {
+	node, err := c.Inventory.UpdateExisting(id, func(n *inventory.Node) error {
+		if n.State != inventory.StateAdopted && n.State != inventory.StateLost {
+			return fmt.Errorf("node %s is %s, only adopted nodes can be reset", id, n.State)
+		}
+		n.Approved = false
+		n.SetState(inventory.StateResetting)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Reset of node %s requested by operator.", id)
+	return node, nil
+}
+
+// Remove forgets a node. Nodes that are, or are about to be, part of the
+// cluster are only removed with force, which also deletes them from
+// Kubernetes. A node that is still advertising itself is rediscovered.
+func (c *Controller) Remove(id string, force bool) error {
+	node, err := c.Inventory.Get(id)
+	if err != nil {
+		return err
+	}
+
+	switch node.State {
+	case inventory.StateAdopting, inventory.StateAdopted, inventory.StateLost, inventory.StateResetting:
+		if !force {
+			return fmt.Errorf("node %s is %s, use force to remove it anyway", id, node.State)
+		}
+		if err := k3s.DeleteNode(id); err != nil {
+			return fmt.Errorf("failed to delete node %s from kubernetes: %w", id, err)
+		}
+	}
+
+	if err := c.Inventory.Delete(id); err != nil {
+		return err
+	}
+
+	log.Infof("Node %s removed by operator.", id)
+	return nil
+}
+
+// RunAdoptions adopts queued nodes, at most workers at a time. It blocks until
+// ctx is cancelled.
+func (c *Controller) RunAdoptions(ctx context.Context, workers int) {
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Go(func() {
+			for {
+				j, ok := c.queue.next(ctx)
+				if !ok {
+					return
+				}
+				c.attempt(ctx, j)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func (c *Controller) enqueue(id string) {
+	if !c.queue.add(id) {
+		log.Debugf("Adoption of %s already queued. Skipping.", id)
+		return
+	}
+	log.Infof("Queued %s for adoption.", id)
+}
+
+// attempt runs a single adoption attempt and schedules a retry if it failed.
+func (c *Controller) attempt(ctx context.Context, j job) {
+	node, err := c.Inventory.Get(j.id)
+	if err != nil {
+		log.Errorf("Failed to load %s from inventory: %v", j.id, err)
+		c.queue.done(j.id)
+		return
+	}
+
+	// An operator may have rejected or reset the node while it was queued.
+	switch node.State {
+	case inventory.StateDiscovered, inventory.StateFailed, inventory.StateAdopting:
+	default:
+		log.Infof("Node %s is %s now. Dropping it from the adoption queue.", node.ID, node.State)
+		c.queue.done(j.id)
+		return
+	}
+
+	err = c.adopt(node, j.attempt)
+	if err == nil {
+		c.queue.done(j.id)
+		return
+	}
+
+	if j.attempt >= c.Retry.MaxAttempts {
+		log.Errorf("Giving up on %s after %d attempts: %v", node.ID, j.attempt, err)
+		c.queue.done(j.id)
+		return
+	}
+
+	delay := c.Retry.delay(j.attempt)
+	log.Warnf("Adoption attempt %d of %s failed, retrying in %s: %v", j.attempt, node.ID, delay.Round(time.Second), err)
+	c.update(node.ID, func(n *inventory.Node) {
+		next := time.Now().Add(delay)
+		n.NextAttemptAt = &next
+	})
+	c.queue.retry(ctx, job{id: j.id, attempt: j.attempt + 1}, delay)
+}
+
+// adopt runs a single adoption attempt and records its outcome. Every attempt
+// creates a fresh join token, so retries never run into an expired one. The
+// fingerprint, if still missing, is fetched over the same connection the node
+// is adopted over, so the role is chosen from the hardware of the node that
+// actually receives the certificate.
+func (c *Controller) adopt(node *inventory.Node, attempt int) error {
+	pending, connErr := ConnectPending(c.Identity, node.IP, int(node.Port))
+	if connErr == nil {
+		defer pending.Close()
+		if node.Fingerprint == nil {
+			node = c.refreshFingerprint(node, pending)
+		}
+	}
+
+	c.roleMu.Lock()
+	role := c.chooseRole(node)
+	started := time.Now()
+	c.update(node.ID, func(n *inventory.Node) {
+		n.Role = role
+		n.LastError = ""
+		n.AdoptionStartedAt = &started
+		n.NextAttemptAt = nil
+		n.AdoptionPhase = ""
+		n.SetState(inventory.StateAdopting)
+	})
+	c.roleMu.Unlock()
+
+	log.Infof("Adopting %s as %s (attempt %d)...", node.ID, role, attempt)
+	err := connErr
+	if err == nil {
+		// The node reaches the controller at whatever address the
+		// controller reaches the node from.
+		var controllerIP string
+		controllerIP, err = c.Net.SourceIP(node.IP)
+		if err != nil {
+			err = fmt.Errorf("failed to pick controller address for %s: %w", node.IP, err)
+		} else {
+			err = pending.Adopt(c.CA, controllerIP, c.Port, c.Cluster, node.ID, role, func(phase pb.AdoptionPhase) {
+				c.update(node.ID, func(n *inventory.Node) {
+					n.AdoptionPhase = phaseName(phase)
+				})
+			})
+		}
+	}
+
+	c.update(node.ID, func(n *inventory.Node) {
+		now := time.Now()
+		record := inventory.Attempt{Role: role, StartedAt: started, FinishedAt: now}
+
+		if err != nil {
+			record.Error = err.Error()
+			n.LastError = err.Error()
+			n.SetState(inventory.StateFailed)
+		} else {
+			n.AdoptedAt = &now
+			n.LastHeartbeat = nil
+			n.SetState(inventory.StateAdopted)
+		}
+
+		n.RecordAttempt(record)
+	})
+
+	return err
+}
+
+// refreshFingerprint fetches the node's hardware fingerprint and records it in
+// the inventory. Failures are logged; the node keeps its previous fingerprint.
+func (c *Controller) refreshFingerprint(node *inventory.Node, pending *PendingNode) *inventory.Node {
+	fp, err := pending.Fingerprint()
+	if err != nil {
+		log.Warnf("Failed to fetch fingerprint of %s: %v", node.ID, err)
+		return node
+	}
+
+	updated, err := c.Inventory.Update(node.ID, func(n *inventory.Node) error {
+		n.Fingerprint = fp
+		return nil
+	})
+	if err != nil {
+		log.Errorf("Failed to update %s in inventory: %v", node.ID, err)
+		return node
+	}
+
+	return updated
+}
+
+func (c *Controller) update(id string, fn func(n *inventory.Node)) {
+	_, err := c.Inventory.Update(id, func(n *inventory.Node) error {
+		fn(n)
+		return nil
+	})
+	if err != nil {
+		log.Errorf("Failed to update %s in inventory: %v", id, err)
+	}
+}
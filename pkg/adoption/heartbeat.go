@@ -0,0 +1,124 @@
+package adoption
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lunarhue/libs-go/log"
+	"github.com/lunarhue/metallic-flock/pkg/inventory"
+	"github.com/lunarhue/metallic-flock/pkg/k3s"
+	pb "github.com/lunarhue/metallic-flock/pkg/proto/adoption/v1"
+)
+
+// Heartbeat records a check-in from an adopted node. A node that was marked
+// lost becomes adopted again as soon as it checks in. A node with a pending
+// reset is told to reconfigure and goes back to being a discovered candidate.
+// Nodes that aren't members, e.g. a stale agent of a node that was removed or
+// rejected, are turned away without touching their state.
+func (c *Controller) Heartbeat(req *pb.HeartbeatRequest) (bool, error) {
+	reconfigure := false
+	_, err := c.Inventory.UpdateExisting(req.NodeId, func(n *inventory.Node) error {
+		switch n.State {
+		case inventory.StateAdopted, inventory.StateLost:
+		case inventory.StateResetting:
+			reconfigure = true
+			n.AdoptedAt = nil
+			n.LastHeartbeat = nil
+			n.Status = nil
+			n.SetState(inventory.StateDiscovered)
+			return nil
+		default:
+			return fmt.Errorf("node %s is %s, not a member of the cluster", n.ID, n.State)
+		}
+
+		now := time.Now()
+		n.LastHeartbeat = &now
+		n.LastSeen = now
+		n.Status = &inventory.Status{
+			K3sUnit:              req.K3SUnitStatus,
+			Load1:                req.Load1,
+			Load5:                req.Load5,
+			Load15:               req.Load15,
+			MemoryTotalBytes:     req.MemoryTotalBytes,
+			MemoryAvailableBytes: req.MemoryAvailableBytes,
+		}
+
+		if n.State == inventory.StateLost {
+			log.Infof("Node %s is back after being lost.", n.ID)
+			n.SetState(inventory.StateAdopted)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if reconfigure {
+		log.Infof("Told %s to revert to pending.", req.NodeId)
+		if err := k3s.DeleteNode(req.NodeId); err != nil {
+			log.Warnf("Failed to remove %s from Kubernetes: %v", req.NodeId, err)
+		}
+		return true, nil
+	}
+
+	log.Debugf("Heartbeat from %s: k3s=%s load=%.2f", req.NodeId, req.K3SUnitStatus, req.Load1)
+	return false, nil
+}
+
+// MonitorHeartbeats marks adopted nodes lost once they haven't checked in for
+// longer than grace. It blocks until ctx is cancelled.
+func (c *Controller) MonitorHeartbeats(ctx context.Context, grace time.Duration) {
+	ticker := time.NewTicker(max(grace/2, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.markLostNodes(grace)
+		}
+	}
+}
+
+func (c *Controller) markLostNodes(grace time.Duration) {
+	nodes, err := c.Inventory.List()
+	if err != nil {
+		log.Errorf("Failed to list inventory: %v", err)
+		return
+	}
+
+	for _, node := range nodes {
+		if node.State != inventory.StateAdopted || !overdue(node, grace) {
+			continue
+		}
+
+		_, err := c.Inventory.UpdateExisting(node.ID, func(n *inventory.Node) error {
+			// Re-check under the write lock in case a heartbeat just came in.
+			if n.State != inventory.StateAdopted || !overdue(n, grace) {
+				return errUnchanged
+			}
+			n.SetState(inventory.StateLost)
+			return nil
+		})
+		switch {
+		case errors.Is(err, errUnchanged), errors.Is(err, inventory.ErrNotFound):
+		case err != nil:
+			log.Errorf("Failed to update %s in inventory: %v", node.ID, err)
+		default:
+			log.Warnf("Node %s missed heartbeats for more than %s. Marked as lost.", node.ID, grace)
+		}
+	}
+}
+
+// overdue reports whether the node's last sign of life is older than grace.
+// Freshly adopted nodes are measured from their adoption time.
+func overdue(node *inventory.Node, grace time.Duration) bool {
+	last := node.LastHeartbeat
+	if last == nil {
+		last = node.AdoptedAt
+	}
+	return last == nil || time.Since(*last) > grace
+}
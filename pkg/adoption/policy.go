@@ -0,0 +1,59 @@
+package adoption
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lunarhue/metallic-flock/pkg/inventory"
+)
+
+// Adoption policies.
+const (
+	// PolicyAuto adopts every candidate as soon as it is discovered.
+	PolicyAuto = "auto"
+	// PolicyManual parks every candidate until an operator approves it.
+	PolicyManual = "manual"
+	// PolicyAllowlist adopts allowlisted candidates and parks the rest.
+	PolicyAllowlist = "allowlist"
+)
+
+// Policy decides which discovered nodes may be adopted without an operator.
+type Policy struct {
+	Mode      string
+	Allowlist []string
+}
+
+func (p Policy) Validate() error {
+	switch p.Mode {
+	case PolicyAuto, PolicyManual, PolicyAllowlist:
+		return nil
+	default:
+		return fmt.Errorf("unknown adoption policy %q (expected %s, %s or %s)", p.Mode, PolicyAuto, PolicyManual, PolicyAllowlist)
+	}
+}
+
+// Admits reports whether node may be adopted right away.
+func (p Policy) Admits(node *inventory.Node) bool {
+	if node.Approved {
+		return true
+	}
+
+	switch p.Mode {
+	case PolicyAuto:
+		return true
+	case PolicyAllowlist:
+		return p.allowlisted(node)
+	default:
+		return false
+	}
+}
+
+// allowlisted matches the node's ID, hostname or advertised MAC address.
+func (p Policy) allowlisted(node *inventory.Node) bool {
+	return slices.ContainsFunc(p.Allowlist, func(entry string) bool {
+		return strings.EqualFold(entry, node.ID) ||
+			(node.Hostname != "" && strings.EqualFold(entry, node.Hostname)) ||
+			(node.Metadata["mac"] != "" && strings.EqualFold(entry, node.Metadata["mac"]))
+	})
+}
@@ -0,0 +1,78 @@
+package adoption
+
+import (
+	"errors"
+	"time"
+
+	"github.com/lunarhue/libs-go/log"
+	"github.com/lunarhue/metallic-flock/pkg/discovery"
+	"github.com/lunarhue/metallic-flock/pkg/inventory"
+)
+
+// errUnchanged aborts an inventory update that turned out to be unnecessary.
+var errUnchanged = errors.New("unchanged")
+
+// HandleDiscovery is the discovery callback for the controller. Pending nodes
+// that show up or change are candidates for adoption. Everything else only
+// tells whether a known node is present.
+func (c *Controller) HandleDiscovery(e discovery.Event) {
+	pending := e.Type.Equal(discovery.TypePending)
+	if pending && e.Op != discovery.OpLost {
+		c.HandleCandidate(e.Candidate)
+		return
+	}
+
+	present := e.Op != discovery.OpLost
+	node, err := c.Inventory.UpdateExisting(e.Name, func(n *inventory.Node) error {
+		// Nodes withdraw their pending advertisement when they join and
+		// their member advertisement when they are reset. Only losing the
+		// one that matches their state means they are gone, and while they
+		// are joining either may go.
+		if !present && (n.State == inventory.StateAdopting || advertisesMember(n.State) == pending) {
+			return errUnchanged
+		}
+		if present {
+			n.LastSeen = time.Now()
+		} else if !n.Present {
+			return errUnchanged
+		}
+		n.SetPresent(present)
+		return nil
+	})
+	switch {
+	case errors.Is(err, errUnchanged):
+		return
+	case errors.Is(err, inventory.ErrNotFound):
+		log.Debugf("Ignoring %s of unknown node %s.", e.Op, e.Name)
+		return
+	case err != nil:
+		log.Errorf("Failed to record presence of %s: %v", e.Name, err)
+		return
+	}
+
+	if present {
+		return
+	}
+	if advertisesMember(node.State) {
+		log.Warnf("Node %s (%s) stopped advertising itself.", node.ID, node.State)
+	} else {
+		log.Infof("Pending node %s (%s) disappeared before it was adopted.", node.ID, node.State)
+	}
+}
+
+// advertisesMember reports whether a node in state advertises itself as a
+// member of the cluster rather than as pending.
+func advertisesMember(state inventory.State) bool {
+	switch state {
+	case inventory.StateAdopted, inventory.StateLost, inventory.StateResetting:
+		return true
+	default:
+		return false
+	}
+}
+
+// isMember reports whether a node in state is, or is about to become, part of
+// the cluster, so the adoption policy has no say over it anymore.
+func isMember(state inventory.State) bool {
+	return state == inventory.StateAdopting || advertisesMember(state)
+}
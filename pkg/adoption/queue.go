@@ -0,0 +1,138 @@
+package adoption
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how failed adoptions are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts before giving up. The node is
+	// tried again once it is rediscovered or approved.
+	MaxAttempts int
+	// Backoff is the delay before the first retry. It doubles with every
+	// further retry up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// delay returns how long to wait after the given failed attempt. Up to a fifth
+// is added as jitter so nodes that failed together don't retry together.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempt && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	return delay + rand.N(delay/5+1)
+}
+
+// job is a node waiting for its next adoption attempt.
+type job struct {
+	id      string
+	attempt int
+}
+
+// queue is a FIFO of nodes waiting to be adopted. A node is in it at most once,
+// from being queued until it is adopted or given up on, including while it
+// waits out a retry backoff. Nodes that re-announce themselves in the
+// meantime are not queued again.
+type queue struct {
+	mu      sync.Mutex
+	ready   []job
+	pending map[string]bool
+	wake    chan struct{}
+}
+
+func (q *queue) init() {
+	if q.pending == nil {
+		q.pending = make(map[string]bool)
+		q.wake = make(chan struct{}, 1)
+	}
+}
+
+// add queues the first attempt for id. It returns false if id is already
+// queued, being adopted or waiting for a retry.
+func (q *queue) add(id string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.init()
+
+	if q.pending[id] {
+		return false
+	}
+	q.pending[id] = true
+	q.push(job{id: id, attempt: 1})
+	return true
+}
+
+// retry queues j again once delay has passed, unless ctx was cancelled by
+// then and nobody is left to work through the queue.
+func (q *queue) retry(ctx context.Context, j job, delay time.Duration) {
+	time.AfterFunc(delay, func() {
+		if ctx.Err() != nil {
+			return
+		}
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		q.push(j)
+	})
+}
+
+// done removes id from the queue so it can be queued again.
+func (q *queue) done(id string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.pending, id)
+}
+
+// next blocks until a job is ready or ctx is cancelled.
+func (q *queue) next(ctx context.Context) (job, bool) {
+	q.mu.Lock()
+	q.init()
+	wake := q.wake
+	q.mu.Unlock()
+
+	for {
+		q.mu.Lock()
+		if len(q.ready) > 0 {
+			j := q.ready[0]
+			q.ready = q.ready[1:]
+			if len(q.ready) > 0 {
+				q.signal()
+			}
+			q.mu.Unlock()
+			return j, true
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return job{}, false
+		case <-wake:
+		}
+	}
+}
+
+// push must be called with mu held.
+func (q *queue) push(j job) {
+	q.init()
+	q.ready = append(q.ready, j)
+	q.signal()
+}
+
+// signal must be called with mu held.
+func (q *queue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,85 @@
+package adoption
+
+import (
+	"strconv"
+
+	"github.com/lunarhue/libs-go/log"
+	"github.com/lunarhue/metallic-flock/pkg/inventory"
+	"github.com/lunarhue/metallic-flock/pkg/k3s"
+)
+
+// ServerPolicy decides which nodes join the control plane as additional k3s
+// servers. The first qualifying nodes are adopted as servers until the cluster
+// has Count of them, the controller included; every other node is an agent.
+type ServerPolicy struct {
+	Count       int
+	MinCPUs     int
+	MinMemoryGB int
+}
+
+// qualifies checks the node's hardware against the minimums. The fingerprint
+// is preferred, the discovery TXT records are the fallback.
+func (p ServerPolicy) qualifies(node *inventory.Node) bool {
+	var cpus int
+	var memoryGB float64
+
+	if fp := node.Fingerprint; fp != nil {
+		for _, cpu := range fp.Cpus {
+			cpus += cpu.Threads
+		}
+		memoryGB = float64(fp.Memory.TotalBytes) / 1024 / 1024 / 1024
+	} else {
+		cpus, _ = strconv.Atoi(node.Metadata["cpu"])
+		memoryGB, _ = strconv.ParseFloat(node.Metadata["mem"], 64)
+	}
+
+	return cpus >= p.MinCPUs && memoryGB >= float64(p.MinMemoryGB)
+}
+
+// holdsServerSeat reports whether the node counts towards the number of
+// servers: it is a server that is joining or part of the cluster.
+func holdsServerSeat(node *inventory.Node) bool {
+	if node.Role != k3s.RoleServer {
+		return false
+	}
+
+	switch node.State {
+	case inventory.StateAdopting, inventory.StateAdopted, inventory.StateLost:
+		return true
+	default:
+		return false
+	}
+}
+
+// chooseRole picks the role node should be adopted as. The caller must hold
+// roleMu until the choice is written to the inventory, so two concurrent
+// adoptions can't both take the last server seat.
+func (c *Controller) chooseRole(node *inventory.Node) string {
+	if c.Servers.Count <= 1 || !c.Servers.qualifies(node) {
+		return k3s.RoleAgent
+	}
+
+	nodes, err := c.Inventory.List()
+	if err != nil {
+		log.Errorf("Failed to count servers, adopting %s as agent: %v", node.ID, err)
+		return k3s.RoleAgent
+	}
+
+	servers := 1 // The controller itself
+	for _, n := range nodes {
+		if n.ID != node.ID && holdsServerSeat(n) {
+			servers++
+		}
+	}
+
+	if servers >= c.Servers.Count {
+		return k3s.RoleAgent
+	}
+
+	if !k3s.UsesEmbeddedEtcd() {
+		log.Warnf("Cluster wants %d servers but k3s is not running embedded etcd (--cluster-init). Adopting %s as agent.", c.Servers.Count, node.ID)
+		return k3s.RoleAgent
+	}
+
+	return k3s.RoleServer
+}
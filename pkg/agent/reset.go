@@ -0,0 +1,28 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lunarhue/metallic-flock/pkg/k3s"
+	"github.com/lunarhue/metallic-flock/pkg/pki"
+)
+
+// Reset returns an adopted node to a blank pending node: k3s is stopped and
+// wiped, the adoption state forgotten and the certificates issued
+// by the controller removed so any controller may adopt the node again.
+func Reset(statePath string, identity *pki.Identity) error {
+	if err := k3s.Stop(); err != nil {
+		return fmt.Errorf("failed to stop k3s: %w", err)
+	}
+
+	if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove agent state: %w", err)
+	}
+
+	if err := identity.Reset(); err != nil {
+		return fmt.Errorf("failed to remove node certificates: %w", err)
+	}
+
+	return nil
+}
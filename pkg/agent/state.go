@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// State is what an adopted node remembers about the cluster it joined, so it
+// can go straight back to heartbeating after a restart.
+type State struct {
+	NodeID         string    `json:"node_id"`
+	ControllerIP   string    `json:"controller_ip"`
+	ControllerPort int       `json:"controller_port"`
+	Role           string    `json:"role"`
+	Cluster        string    `json:"cluster,omitempty"`
+	AdoptedAt      time.Time `json:"adopted_at"`
+}
+
+// LoadState returns the persisted state, or nil if the node was never adopted.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent state: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse agent state %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+func SaveState(path string, state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode agent state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write agent state: %w", err)
+	}
+	return nil
+}
@@ -3,6 +3,9 @@ package config
 import (
 	"embed"
 	"fmt"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/lunarhue/libs-go/config"
 	"github.com/lunarhue/libs-go/log"
@@ -15,22 +18,132 @@ type NixOSConfig struct {
 	NixOSPath string `mapstructure:"nixos_path" description:"Path to the NixOS configuration file"`
 }
 
+type AdoptionConfig struct {
+	Policy    string   `mapstructure:"policy" description:"Adoption policy (auto, manual, allowlist)"`
+	Allowlist []string `mapstructure:"allowlist" description:"Node IDs, hostnames or MAC addresses adopted without approval in allowlist mode"`
+
+	Workers         int           `mapstructure:"workers" description:"Number of nodes adopted concurrently"`
+	MaxAttempts     int           `mapstructure:"max_attempts" description:"Adoption attempts per node before giving up until it is rediscovered or approved again"`
+	RetryBackoff    time.Duration `mapstructure:"retry_backoff" description:"Delay before the first adoption retry, doubled for every further retry"`
+	MaxRetryBackoff time.Duration `mapstructure:"max_retry_backoff" description:"Upper bound for the delay between adoption retries"`
+}
+
+type HeartbeatConfig struct {
+	Interval    time.Duration `mapstructure:"interval" description:"How often adopted nodes check in with the controller"`
+	GracePeriod time.Duration `mapstructure:"grace_period" description:"How long the controller waits for a heartbeat before marking a node lost"`
+}
+
+type AutoConfig struct {
+	ScanWindow time.Duration `mapstructure:"scan_window" description:"How long auto mode watches for a controller and other candidates before electing one"`
+	ScanJitter time.Duration `mapstructure:"scan_jitter" description:"Random extra time added to the scan window"`
+}
+
+type NetworkConfig struct {
+	Interfaces   []string `mapstructure:"interfaces" description:"Network interfaces to advertise and listen on (all if empty)"`
+	CIDRs        []string `mapstructure:"cidrs" description:"Networks to advertise and listen on (all if empty); peer addresses within them are preferred"`
+	PreferFamily string   `mapstructure:"prefer_family" description:"Address family preferred when a peer has several addresses (ipv4, ipv6)"`
+	DualStack    bool     `mapstructure:"dual_stack" description:"Register nodes with k3s under an IPv4 and an IPv6 address"`
+}
+
+type DiscoveryConfig struct {
+	Method   string                `mapstructure:"method" description:"How nodes find each other (zeroconf, static, dns-sd)"`
+	Interval time.Duration         `mapstructure:"interval" description:"How often static peers and DNS-SD records are polled"`
+	Static   StaticDiscoveryConfig `mapstructure:"static"`
+	DNSSD    DNSSDConfig           `mapstructure:"dns_sd"`
+}
+
+type StaticDiscoveryConfig struct {
+	Nodes       []string `mapstructure:"nodes" description:"Addresses (host:port) of nodes the controller polls for adoption"`
+	Controllers []string `mapstructure:"controllers" description:"Addresses (host:port) of controllers adopted nodes fall back to when theirs is unreachable"`
+}
+
+type DNSSDConfig struct {
+	Domain string `mapstructure:"domain" description:"Domain holding the _hive-pending._tcp and _hive-controller._tcp records"`
+	Server string `mapstructure:"server" description:"DNS server (host:port) to query, the first nameserver in /etc/resolv.conf if empty"`
+}
+
+type ClusterConfig struct {
+	Name              string `mapstructure:"name" description:"Name of the cluster. Controllers advertise it and only adopt unpinned nodes or nodes pinned to it, agents with a name are pinned to that cluster"`
+	Servers           int    `mapstructure:"servers" description:"Number of k3s servers the cluster should have, including the controller"`
+	ServerMinCPUs     int    `mapstructure:"server_min_cpus" description:"Minimum CPU threads a node needs to be adopted as a server"`
+	ServerMinMemoryGB int    `mapstructure:"server_min_memory_gb" description:"Minimum memory in GB a node needs to be adopted as a server"`
+}
+
 type Config struct {
 	DefaultPort int    `mapstructure:"default_port" description:"Port to listen on for incoming connections"`
-	Mode        string `mapstructure:"mode" description:"Operation mode (server, agent, auto)"`
+	Mode        string `mapstructure:"mode" description:"Operation mode (agent, controller, auto), server is an alias for controller"`
 	K3sPath     string `mapstructure:"k3s_path" description:"Path to the K3s binary"`
+	StateDir    string `mapstructure:"state_dir" description:"Directory for persistent state such as certificates"`
+
+	ControllerSocket string          `mapstructure:"controller_socket" description:"Unix socket the controller serves its management API on"`
+	Adoption         AdoptionConfig  `mapstructure:"adoption"`
+	Heartbeat        HeartbeatConfig `mapstructure:"heartbeat"`
+	Cluster          ClusterConfig   `mapstructure:"cluster"`
+	Auto             AutoConfig      `mapstructure:"auto"`
+	Discovery        DiscoveryConfig `mapstructure:"discovery"`
+	Network          NetworkConfig   `mapstructure:"network"`
 
 	LogLevel string `mapstructure:"log_level" description:"Console logging level (debug, info, warn, error)"`
 	LogFile  string `mapstructure:"log_file" description:"File to log to (empty for console only)"`
 }
 
+// overrideConfigFile is read from the working directory if it exists.
+const overrideConfigFile = "config.yaml"
+
 func Load() (*Config, error) {
-	cfg, err := config.LoadConfig[Config](&defaultConfigFile, "default.config.yaml", "config.yaml", "METALLIC_FLOCK")
+	// A missing override file is fatal to LoadConfig, but the systemd unit
+	// runs without one and is configured through the environment.
+	override := overrideConfigFile
+	if _, err := os.Stat(override); err != nil {
+		override = ""
+	}
+
+	cfg, err := config.LoadConfig[Config](&defaultConfigFile, "default.config.yaml", override, "METALLIC_FLOCK")
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
 	log.SetLevelFromString(cfg.LogLevel)
 
 	return cfg, nil
 }
+
+// validate rejects values the rest of the program can't run with.
+func (c *Config) validate() error {
+	hb := c.Heartbeat
+	if hb.Interval <= 0 {
+		return fmt.Errorf("heartbeat.interval must be positive, got %s", hb.Interval)
+	}
+	if hb.GracePeriod <= 0 {
+		return fmt.Errorf("heartbeat.grace_period must be positive, got %s", hb.GracePeriod)
+	}
+	// Nodes would be marked lost between two heartbeats otherwise.
+	if hb.GracePeriod < hb.Interval {
+		return fmt.Errorf("heartbeat.grace_period (%s) must not be shorter than heartbeat.interval (%s)", hb.GracePeriod, hb.Interval)
+	}
+	return nil
+}
+
+// PKIDir is where the CA, node key and certificates are kept.
+func (c *Config) PKIDir() string {
+	return filepath.Join(c.StateDir, "pki")
+}
+
+// InventoryPath is the controller's node inventory database.
+func (c *Config) InventoryPath() string {
+	return filepath.Join(c.StateDir, "inventory.db")
+}
+
+// NodeIDPath is where the node's hardware-derived ID is kept.
+func (c *Config) NodeIDPath() string {
+	return filepath.Join(c.StateDir, "node-id")
+}
+
+// AgentStatePath is where an adopted node remembers its controller.
+func (c *Config) AgentStatePath() string {
+	return filepath.Join(c.StateDir, "agent.json")
+}
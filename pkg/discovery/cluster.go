@@ -0,0 +1,28 @@
+package discovery
+
+// txtCluster is the TXT record naming the cluster a controller runs or a
+// pending node is pinned to. Several flocks can share a network as long as
+// their controllers have different names.
+const txtCluster = "cluster"
+
+// ClusterRecords are the TXT records that pin a node to cluster. There are
+// none if cluster is empty.
+func ClusterRecords(cluster string) []string {
+	if cluster == "" {
+		return nil
+	}
+	return []string{txtCluster + "=" + cluster}
+}
+
+// joinable reports whether a node pinned to cluster may join the controller
+// advertising meta. Unpinned nodes join any controller.
+func joinable(cluster string, meta map[string]string) bool {
+	return cluster == "" || meta[txtCluster] == cluster
+}
+
+// adoptable reports whether the controller of cluster may adopt the node
+// advertising meta. Nodes pinned to another cluster are left alone.
+func adoptable(cluster string, meta map[string]string) bool {
+	pinned := meta[txtCluster]
+	return pinned == "" || pinned == cluster
+}
@@ -1,24 +1,115 @@
 package discovery
 
 import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strconv"
 	"time"
 
 	"github.com/lunarhue/libs-go/log"
+	"github.com/lunarhue/metallic-flock/pkg/agent"
+	"github.com/lunarhue/metallic-flock/pkg/k3s"
+	"github.com/lunarhue/metallic-flock/pkg/pki"
+	pb "github.com/lunarhue/metallic-flock/pkg/proto/adoption/v1"
+	"github.com/shirou/gopsutil/v4/load"
+	"github.com/shirou/gopsutil/v4/mem"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
-func RunComputeMode() {
+// Consecutive heartbeat failures before the controller is considered gone and
+// we go looking for it again.
+const maxHeartbeatFailures = 3
+
+// RunComputeMode heartbeats the controller until it asks the node to revert
+// to pending. Meanwhile the node advertises itself as a member of the
+// cluster, which the controller takes as a sign of life besides heartbeats.
+func RunComputeMode(d Discoverer, NodeID string, Port uint16, identity *pki.Identity, state *agent.State, interval time.Duration) {
 	log.Info("State: COMPUTE. Connecting to Cluster...")
 
+	if stop, err := d.Advertise(memberService(NodeID, Port, state.Cluster)); err != nil {
+		log.Warnf("Failed to advertise cluster membership: %v", err)
+	} else {
+		defer stop()
+	}
+
+	tlsConfig, err := identity.ClientTLSConfig(pki.ControllerServerName)
+	if err != nil {
+		log.Panicf("Failed to set up controller TLS: %v", err)
+	}
+
+	controllerIP := state.ControllerIP
+	failures := 0
+
 	// Survivability Loop
 	for {
-		controllerIP := ScanForControllers(5 * time.Second)
+		reconfigure, err := sendHeartbeat(tlsConfig, controllerIP, state.ControllerPort, NodeID, state.Role)
+		if reconfigure {
+			log.Info("Controller requested reconfiguration. Reverting to pending...")
+			return
+		}
 
-		if controllerIP == "" {
-			log.Info("Lost Controller! Scanning...")
+		if err == nil {
+			if failures > 0 {
+				log.Infof("Connected to Controller at %s", controllerIP)
+			}
+			failures = 0
 		} else {
-			log.Infof("Connected to Controller at %s", controllerIP)
+			failures++
+			log.Warnf("Heartbeat to %s failed (%d/%d): %v", controllerIP, failures, maxHeartbeatFailures, err)
+
+			if failures >= maxHeartbeatFailures {
+				log.Info("Lost Controller! Scanning...")
+				if ip := ScanForControllers(d, state.Cluster, 5*time.Second); ip != "" && ip != controllerIP {
+					log.Infof("Found Controller at %s", ip)
+					controllerIP = ip
+				}
+			}
 		}
 
-		time.Sleep(10 * time.Second)
+		time.Sleep(interval)
+	}
+}
+
+func sendHeartbeat(tlsConfig *tls.Config, controllerIP string, controllerPort int, nodeID, role string) (bool, error) {
+	conn, err := grpc.NewClient(net.JoinHostPort(controllerIP, strconv.Itoa(controllerPort)), grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	if err != nil {
+		return false, err
 	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := pb.NewFlockServiceClient(conn).Heartbeat(ctx, collectHeartbeat(nodeID, role))
+	if err != nil {
+		return false, err
+	}
+	return resp.Reconfigure, nil
+}
+
+// collectHeartbeat gathers the node's current health. Metrics that can't be
+// read are left at zero rather than failing the heartbeat.
+func collectHeartbeat(nodeID, role string) *pb.HeartbeatRequest {
+	req := &pb.HeartbeatRequest{
+		NodeId:        nodeID,
+		Status:        "compute",
+		K3SUnitStatus: k3s.UnitStatus(k3s.UnitName(role)),
+	}
+
+	if avg, err := load.Avg(); err == nil {
+		req.Load1, req.Load5, req.Load15 = avg.Load1, avg.Load5, avg.Load15
+	} else {
+		log.Debugf("Failed to read load average: %v", err)
+	}
+
+	if vm, err := mem.VirtualMemory(); err == nil {
+		req.MemoryTotalBytes = vm.Total
+		req.MemoryAvailableBytes = vm.Available
+	} else {
+		log.Debugf("Failed to read memory usage: %v", err)
+	}
+
+	return req
 }
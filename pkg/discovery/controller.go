@@ -13,7 +13,20 @@ import (
 	"github.com/lunarhue/metallic-flock/pkg/k3s"
 )
 
-func RunControllerMode(NodeID string, Port uint16, callback func(ip string, role string)) {
+// Candidate is a pending node seen advertising itself on the network.
+type Candidate struct {
+	// Name is the node ID the candidate advertises itself under.
+	Name     string
+	Hostname string
+	IP       string
+	Port     uint16
+	Metadata map[string]string
+}
+
+// RunControllerMode starts the k3s server, advertises the controller of
+// cluster and hands every change to pending nodes it may adopt and to the
+// members of its cluster to callback.
+func RunControllerMode(d Discoverer, NodeID string, Port uint16, cluster string, callback func(e Event)) {
 	log.Info("State: CONTROLLER. Managing Cluster...")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -26,44 +39,54 @@ func RunControllerMode(NodeID string, Port uint16, callback func(ip string, role
 	log.Infof("K3s Server started successfully.")
 
 	me := zeroconf.NewService(TypeController, NodeID, Port)
+	me.Text = ClusterRecords(cluster)
 
-	onNodeFound := func(e zeroconf.Event) {
-		log.Infof("[DISCOVERY] Saw Service: %s | Operation: %v", e.Name, e.Op)
+	onEvent := func(e Event) {
+		c, meta := e.Candidate, e.Metadata
+		if !adoptable(cluster, meta) {
+			log.Debugf("Ignoring %s, it belongs to cluster %q.", c.Name, meta[txtCluster])
+			return
+		}
 
-		if e.Op == zeroconf.OpAdded && len(e.Addrs) > 0 {
-			meta := parseMetadata(e.Text)
+		if e.Op != OpFound || !e.Type.Equal(TypePending) {
+			log.Debugf("[DISCOVERY] %s %s: %s", e.Type.Name, e.Op, c.Name)
+			callback(e)
+			return
+		}
 
-			log.Infof("------------------------------------------------")
-			log.Infof("   CANDIDATE FOUND: %s", e.Name)
-			log.Infof("   IP:   %s", e.Addrs[0])
-			log.Infof("   OS:   %s (%s)", meta["os"], meta["distro"])
-			log.Infof("   HW:   %s Threads / %s GB RAM / %s GB Disk", meta["cpu"], meta["mem"], meta["disk"])
-			log.Infof("   MAC:  %s", meta["mac"])
-			log.Infof("------------------------------------------------")
+		log.Infof("------------------------------------------------")
+		log.Infof("   CANDIDATE FOUND: %s (%s)", c.Name, c.Hostname)
+		log.Infof("   IP:   %s", c.IP)
+		log.Infof("   OS:   %s (%s)", meta["os"], meta["distro"])
+		log.Infof("   HW:   %s Threads / %s GB RAM / %s GB Disk", meta["cpu"], meta["mem"], meta["disk"])
+		log.Infof("   MAC:  %s", meta["mac"])
+		log.Infof("------------------------------------------------")
 
-			log.Infof("Found new node: %s [%v]. Auto-adopting...", e.Name, e.Addrs)
+		log.Infof("Found new node: %s [%s].", c.Name, c.IP)
 
-			ip := e.Addrs[0].String()
+		callback(e)
+	}
 
-			go callback(ip, "agent")
-		}
+	stop, err := d.Advertise(me)
+	if err != nil {
+		log.Panicf("Failed to advertise controller: %v", err)
 	}
+	defer stop()
 
-	client, err := zeroconf.New().
-		Publish(me).
-		Browse(onNodeFound, TypePending).
-		Open()
+	sigCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
 
-	if err != nil {
-		log.Panicf("Failed to start zeroconf: %v", err)
+	for _, typ := range []zeroconf.Type{TypePending, TypeMember} {
+		go func() {
+			if err := d.Browse(sigCtx, typ, onEvent); err != nil {
+				log.Panicf("Failed to browse for %s: %v", typ.Name, err)
+			}
+		}()
 	}
-	defer client.Close()
 
 	log.Info("Controller Beacon Active & Scanning...")
 
-	sig := make(chan os.Signal, 1)
-	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
-	<-sig
+	<-sigCtx.Done()
 	log.Info("Shutting down controller...")
 }
 
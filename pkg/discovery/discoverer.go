@@ -0,0 +1,167 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/lunarhue/libs-go/log"
+	zeroconf "github.com/lunarhue/metallic-flock-zeroconf"
+	"github.com/lunarhue/metallic-flock/pkg/network"
+)
+
+// Discovery methods selectable per node.
+const (
+	MethodZeroconf = "zeroconf"
+	MethodStatic   = "static"
+	MethodDNSSD    = "dns-sd"
+)
+
+// Op says what happened to a discovered service.
+type Op int
+
+const (
+	// OpFound means the service showed up.
+	OpFound Op = iota
+	// OpUpdated means the service changed its address or TXT records.
+	OpUpdated
+	// OpLost means the service stopped advertising itself or expired.
+	OpLost
+)
+
+func (op Op) String() string {
+	switch op {
+	case OpFound:
+		return "found"
+	case OpUpdated:
+		return "updated"
+	case OpLost:
+		return "lost"
+	default:
+		return fmt.Sprintf("Op(%d)", int(op))
+	}
+}
+
+// Event is a change to a service seen by a Discoverer. For OpLost the
+// Candidate is the service as it was last seen.
+type Event struct {
+	Op   Op
+	Type zeroconf.Type
+	Candidate
+}
+
+// Discoverer finds pending nodes and controllers and, where the method
+// supports it, announces this node to the others.
+type Discoverer interface {
+	// Advertise announces svc until stop is called. Methods that can't
+	// announce anything return a stop that does nothing.
+	Advertise(svc *zeroconf.Service) (stop func(), err error)
+
+	// Browse calls onEvent for every service of typ that shows up, changes
+	// or goes away until ctx is done.
+	Browse(ctx context.Context, typ zeroconf.Type, onEvent func(Event)) error
+}
+
+// sighting is a service seen by a polling discoverer. rev changes whenever the
+// service changes in a way the controller should hear about again.
+type sighting struct {
+	Candidate
+	rev string
+}
+
+// pollChanges calls list every interval and reports the services of typ that
+// are new, changed or gone since the previous call. A failed call says nothing
+// about the services, so it is logged and the previous result kept.
+func pollChanges(ctx context.Context, interval time.Duration, typ zeroconf.Type, list func(ctx context.Context) ([]sighting, error), onEvent func(Event)) error {
+	last := make(map[string]sighting)
+	for {
+		seen, err := list(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Warnf("Failed to poll for %s: %v", typ, err)
+		}
+		if err == nil {
+			current := make(map[string]sighting)
+			for _, s := range seen {
+				current[s.Name] = s
+				if prev, ok := last[s.Name]; !ok {
+					onEvent(Event{Op: OpFound, Type: typ, Candidate: s.Candidate})
+				} else if prev.rev != s.rev {
+					onEvent(Event{Op: OpUpdated, Type: typ, Candidate: s.Candidate})
+				}
+			}
+			// A cancelled poll sees nothing; that doesn't mean everything left.
+			if ctx.Err() != nil {
+				return nil
+			}
+			for name, s := range last {
+				if _, ok := current[name]; !ok {
+					onEvent(Event{Op: OpLost, Type: typ, Candidate: s.Candidate})
+				}
+			}
+			last = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// ScanForControllers looks for a controller for a specific duration. A node
+// pinned to a cluster only accepts controllers of that cluster.
+func ScanForControllers(d Discoverer, cluster string, duration time.Duration) string {
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	foundIP := make(chan string, 1)
+	onEvent := func(e Event) {
+		c := e.Candidate
+		if e.Op == OpLost || c.IP == "" || !joinable(cluster, c.Metadata) {
+			return
+		}
+
+		log.Infof("Discovered Controller: %s", c.Name)
+		select {
+		case foundIP <- c.IP:
+		default:
+		}
+		cancel()
+	}
+
+	if err := d.Browse(ctx, TypeController, onEvent); err != nil {
+		log.Errorf("Failed to scan for controllers: %v", err)
+		return ""
+	}
+
+	select {
+	case ip := <-foundIP:
+		return ip
+	default:
+		return ""
+	}
+}
+
+// resolveHost returns host if it is an IP address, or the address sel
+// prefers among those it resolves to.
+func resolveHost(ctx context.Context, sel *network.Selector, host string) (string, error) {
+	if net.ParseIP(host) != nil {
+		return host, nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return "", err
+	}
+	if len(addrs) == 0 {
+		return "", fmt.Errorf("%s has no addresses", host)
+	}
+
+	ips := make([]net.IP, len(addrs))
+	for i, addr := range addrs {
+		ips[i] = addr.IP
+	}
+	return sel.Prefer(ips).String(), nil
+}
@@ -0,0 +1,190 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lunarhue/libs-go/log"
+	zeroconf "github.com/lunarhue/metallic-flock-zeroconf"
+	"github.com/lunarhue/metallic-flock/pkg/network"
+	"github.com/miekg/dns"
+)
+
+// DNSSD discovers nodes through unicast DNS-SD (RFC 6763): a PTR record per
+// node under _hive-pending._tcp.<domain> or _hive-controller._tcp.<domain>,
+// with SRV and TXT records for each. The records are managed outside of
+// metallic-flock, so nodes can't announce themselves this way.
+type DNSSD struct {
+	// Domain the service records live in, e.g. flock.example.com.
+	Domain string
+	// Server is the host:port of the DNS server to query. The first
+	// nameserver in /etc/resolv.conf is used if it is empty.
+	Server string
+	// Interval is how often the records are polled.
+	Interval time.Duration
+	// Net picks among the addresses of an SRV target.
+	Net *network.Selector
+}
+
+func (d *DNSSD) Advertise(svc *zeroconf.Service) (func(), error) {
+	log.Infof("DNS-SD discovery can't announce %s. Register it under %s.%s.", svc.Name, svc.Type.Name, d.Domain)
+	return func() {}, nil
+}
+
+func (d *DNSSD) Browse(ctx context.Context, typ zeroconf.Type, onEvent func(Event)) error {
+	if d.Domain == "" {
+		return fmt.Errorf("no DNS-SD domain configured")
+	}
+
+	server := d.Server
+	if server == "" {
+		conf, err := dns.ClientConfigFromFile("/etc/resolv.conf")
+		if err != nil {
+			return fmt.Errorf("failed to read resolver config: %w", err)
+		}
+		if len(conf.Servers) == 0 {
+			return fmt.Errorf("no nameserver in /etc/resolv.conf")
+		}
+		server = net.JoinHostPort(conf.Servers[0], conf.Port)
+	}
+
+	service := dns.Fqdn(typ.Name + "." + d.Domain)
+	list := func(ctx context.Context) ([]sighting, error) {
+		seen, err := d.lookup(ctx, server, service)
+		if err != nil {
+			return nil, fmt.Errorf("DNS-SD lookup of %s failed: %w", service, err)
+		}
+		return seen, nil
+	}
+
+	return pollChanges(ctx, d.Interval, typ, list, onEvent)
+}
+
+// lookup resolves every instance of service. Instances whose records are
+// incomplete are skipped.
+func (d *DNSSD) lookup(ctx context.Context, server, service string) ([]sighting, error) {
+	ptrs, err := query(ctx, server, service, dns.TypePTR)
+	if err != nil {
+		return nil, err
+	}
+
+	var seen []sighting
+	for _, rr := range ptrs {
+		ptr, ok := rr.(*dns.PTR)
+		if !ok {
+			continue
+		}
+
+		instance, err := d.resolveInstance(ctx, server, ptr.Ptr, service)
+		if err != nil {
+			log.Debugf("Skipping DNS-SD instance %s: %v", ptr.Ptr, err)
+			continue
+		}
+		seen = append(seen, instance)
+	}
+	return seen, nil
+}
+
+func (d *DNSSD) resolveInstance(ctx context.Context, server, instance, service string) (sighting, error) {
+	srvs, err := query(ctx, server, instance, dns.TypeSRV)
+	if err != nil {
+		return sighting{}, err
+	}
+	var srv *dns.SRV
+	for _, rr := range srvs {
+		if s, ok := rr.(*dns.SRV); ok {
+			srv = s
+			break
+		}
+	}
+	if srv == nil {
+		return sighting{}, fmt.Errorf("no SRV record")
+	}
+
+	var text []string
+	txts, err := query(ctx, server, instance, dns.TypeTXT)
+	if err != nil {
+		return sighting{}, err
+	}
+	for _, rr := range txts {
+		if t, ok := rr.(*dns.TXT); ok {
+			text = append(text, t.Txt...)
+		}
+	}
+
+	ip, err := d.resolveTarget(ctx, server, srv.Target)
+	if err != nil {
+		return sighting{}, err
+	}
+
+	meta := parseMetadata(text)
+	hostname := meta["hostname"]
+	if hostname == "" {
+		hostname = strings.TrimSuffix(srv.Target, ".")
+	}
+
+	c := Candidate{
+		Name:     strings.TrimSuffix(instance, "."+service),
+		Hostname: hostname,
+		IP:       ip,
+		Port:     srv.Port,
+		Metadata: meta,
+	}
+
+	slices.Sort(text)
+	rev := ip + " " + strconv.Itoa(int(srv.Port)) + " " + strings.Join(text, " ")
+
+	return sighting{Candidate: c, rev: rev}, nil
+}
+
+// resolveTarget returns the preferred address of an SRV target.
+func (d *DNSSD) resolveTarget(ctx context.Context, server, target string) (string, error) {
+	var ips []net.IP
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		rrs, err := query(ctx, server, target, qtype)
+		if err != nil {
+			return "", err
+		}
+		for _, rr := range rrs {
+			switch a := rr.(type) {
+			case *dns.A:
+				ips = append(ips, a.A)
+			case *dns.AAAA:
+				ips = append(ips, a.AAAA)
+			}
+		}
+	}
+	if len(ips) == 0 {
+		return "", fmt.Errorf("%s has no addresses", target)
+	}
+	return d.Net.Prefer(ips).String(), nil
+}
+
+// query returns the answers to a single question. A name that doesn't exist
+// has no answers rather than failing.
+func query(ctx context.Context, server, name string, qtype uint16) ([]dns.RR, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(name), qtype)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	resp, _, err := new(dns.Client).ExchangeContext(ctx, m, server)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.Rcode {
+	case dns.RcodeSuccess:
+		return resp.Answer, nil
+	case dns.RcodeNameError:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("%s %s: %s", name, dns.TypeToString[qtype], dns.RcodeToString[resp.Rcode])
+	}
+}
@@ -0,0 +1,172 @@
+package discovery
+
+import (
+	"cmp"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lunarhue/libs-go/log"
+	zeroconf "github.com/lunarhue/metallic-flock-zeroconf"
+)
+
+// TXT records auto-mode nodes add to their pending advertisement. Nodes that
+// lost an election keep advertising whom they elected, so nodes booting while
+// the winner is still starting its controller don't elect a second one.
+const (
+	txtMode    = "mode"
+	txtElected = "elected"
+	modeAuto   = "auto"
+)
+
+// ElectedRecords are the TXT records a node that lost the election to winner
+// advertises while it is pending.
+func ElectedRecords(winner string) []string {
+	return []string{txtMode + "=" + modeAuto, txtElected + "=" + winner}
+}
+
+// Elector is an auto-mode node taking part in a controller election.
+type Elector struct {
+	ID       string
+	CPUs     int
+	MemoryGB int
+}
+
+// Result is the outcome of an election.
+type Result struct {
+	// Controller is true if this node should become the controller.
+	Controller bool
+	// Winner is the ID of the node that becomes the controller, empty if
+	// an existing controller was found.
+	Winner string
+	// ControllerIP is the address of an existing controller, if one was
+	// found.
+	ControllerIP string
+}
+
+// better orders electors so the best candidate comes first: most CPUs, then
+// most memory, then the lowest ID so identical machines still agree.
+func better(a, b Elector) int {
+	return cmp.Or(
+		cmp.Compare(b.CPUs, a.CPUs),
+		cmp.Compare(b.MemoryGB, a.MemoryGB),
+		cmp.Compare(a.ID, b.ID),
+	)
+}
+
+// RunElection advertises this node as an auto-mode candidate and watches the
+// network for window plus up to jitter. An existing controller or an already
+// elected winner ends the election for this node. Otherwise every auto-mode
+// node visible in the window is a candidate and the best one becomes the
+// controller; every node computes the same winner from the same view. Only
+// nodes of the same cluster take part, and a node pinned to a cluster ignores
+// controllers of other clusters.
+//
+// Elections always run over mDNS, whatever discovery method the node uses.
+func (z Zeroconf) RunElection(id, cluster string, port uint16, window, jitter time.Duration) Result {
+	me := pendingService(id, port, append([]string{txtMode + "=" + modeAuto}, ClusterRecords(cluster)...))
+
+	var mu sync.Mutex
+	candidates := map[string]Elector{id: electorFrom(id, parseMetadata(me.Text))}
+	var result *Result
+	decided := make(chan struct{})
+
+	// decide must be called with mu held.
+	decide := func(r Result) {
+		if result == nil {
+			result = &r
+			close(decided)
+		}
+	}
+
+	onEvent := func(e zeroconf.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if e.Name == id {
+			return
+		}
+
+		meta := parseMetadata(e.Text)
+
+		if e.Type.Equal(TypeController) {
+			if e.Op != zeroconf.OpRemoved && len(e.Addrs) > 0 && joinable(cluster, meta) {
+				log.Infof("Election: found controller %s.", e.Name)
+				decide(Result{ControllerIP: z.preferredAddr(e).String()})
+			}
+			return
+		}
+
+		if e.Op == zeroconf.OpRemoved {
+			delete(candidates, e.Name)
+			return
+		}
+
+		if meta[txtCluster] != cluster {
+			return
+		}
+		if winner := meta[txtElected]; winner != "" {
+			log.Infof("Election: %s already elected %s.", e.Name, winner)
+			decide(Result{Winner: winner, Controller: winner == id})
+			return
+		}
+		if meta[txtMode] == modeAuto {
+			candidates[e.Name] = electorFrom(e.Name, meta)
+		}
+	}
+
+	client, err := z.client().
+		Publish(me).
+		Browse(onEvent, TypePending, TypeController).
+		Open()
+	if err != nil {
+		log.Errorf("Election: failed to start zeroconf, assuming no peers: %v", err)
+		return Result{Controller: true, Winner: id}
+	}
+	defer client.Close()
+
+	// The jitter keeps nodes powered on together from all deciding on the
+	// same instant with the same incomplete view.
+	wait := window
+	if jitter > 0 {
+		wait += rand.N(jitter)
+	}
+	log.Infof("Election: watching for controllers and candidates for %s...", wait.Round(time.Second))
+
+	select {
+	case <-decided:
+	case <-time.After(wait):
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if result == nil {
+		decide(elect(id, candidates))
+	}
+	return *result
+}
+
+func elect(id string, candidates map[string]Elector) Result {
+	electors := make([]Elector, 0, len(candidates))
+	for _, c := range candidates {
+		electors = append(electors, c)
+	}
+	slices.SortFunc(electors, better)
+
+	winner := electors[0]
+	log.Infof("Election: %d candidates, %s wins with %d CPUs and %d GB memory.", len(electors), winner.ID, winner.CPUs, winner.MemoryGB)
+
+	return Result{Controller: winner.ID == id, Winner: winner.ID}
+}
+
+// electorFrom reads a candidate's hardware from its TXT records. Memory is
+// rounded to whole GB so identical machines compare equal.
+func electorFrom(id string, meta map[string]string) Elector {
+	cpus, _ := strconv.Atoi(meta["cpu"])
+	memoryGB, _ := strconv.ParseFloat(meta["mem"], 64)
+
+	return Elector{ID: id, CPUs: cpus, MemoryGB: int(math.Round(memoryGB))}
+}
@@ -4,16 +4,19 @@ import (
 	"github.com/lunarhue/libs-go/log"
 )
 
-func RunPendingMode(NodeID string, Port uint16) {
+// RunPendingMode advertises the node until it is adopted. extra is appended
+// to the TXT records.
+func RunPendingMode(d Discoverer, NodeID string, Port uint16, adopted <-chan struct{}, extra ...string) {
 	log.Info("State: PENDING. Broadcasting availability...")
 
 	// 1. Advertise ourselves
-	client, err := StartAgentBroadcast(NodeID, Port)
+	stop, err := d.Advertise(pendingService(NodeID, Port, extra))
 	if err != nil {
 		log.Panicf("Failed to start broadcast: %v", err)
 	}
-	defer client.Close()
+	defer stop()
 
-	// 2. Wait indefinitely
-	select {}
+	// 2. Wait for a controller to adopt us
+	<-adopted
+	log.Info("Adopted! Stopping broadcast.")
 }
@@ -0,0 +1,149 @@
+package discovery
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/lunarhue/libs-go/log"
+	zeroconf "github.com/lunarhue/metallic-flock-zeroconf"
+	"github.com/lunarhue/metallic-flock/pkg/network"
+)
+
+// Static discovers nodes from fixed address lists, for networks where
+// multicast doesn't reach. Nodes can't announce themselves this way; the
+// controller polls the nodes it is told about instead.
+type Static struct {
+	// Nodes are the host:port addresses of the FlockService of nodes the
+	// controller may adopt.
+	Nodes []string
+	// Controllers are the host:port addresses of controllers adopted nodes
+	// look for when theirs stops answering.
+	Controllers []string
+	// Cluster is the cluster the listed controllers run. They are trusted
+	// to be the right ones for this node.
+	Cluster string
+	// Interval is how often the addresses are polled.
+	Interval time.Duration
+	// Net picks among the addresses a host name resolves to.
+	Net *network.Selector
+	// TLS is used to fetch a node's certificate, which carries its node ID.
+	TLS *tls.Config
+}
+
+func (s *Static) Advertise(svc *zeroconf.Service) (func(), error) {
+	return func() {}, nil
+}
+
+func (s *Static) Browse(ctx context.Context, typ zeroconf.Type, onEvent func(Event)) error {
+	var list func(ctx context.Context) ([]sighting, error)
+	switch {
+	// Static nodes can't say whether they are pending or members, so both
+	// are every listed node that answers.
+	case typ.Equal(TypePending), typ.Equal(TypeMember):
+		if s.TLS == nil {
+			return fmt.Errorf("static discovery of nodes needs a TLS config")
+		}
+		list = s.pollNodes
+	case typ.Equal(TypeController):
+		list = s.pollControllers
+	default:
+		return fmt.Errorf("static discovery does not support %s", typ)
+	}
+
+	return pollChanges(ctx, s.Interval, typ, list, onEvent)
+}
+
+// pollNodes probes every node. A node's certificate changes when it is
+// adopted or reset, so it is reported again whenever it does.
+func (s *Static) pollNodes(ctx context.Context) ([]sighting, error) {
+	var seen []sighting
+	for _, addr := range s.Nodes {
+		node, err := s.probeNode(ctx, addr)
+		if err != nil {
+			log.Debugf("Static node %s is unreachable: %v", addr, err)
+			continue
+		}
+		seen = append(seen, node)
+	}
+	return seen, nil
+}
+
+func (s *Static) probeNode(ctx context.Context, addr string) (sighting, error) {
+	host, ip, port, err := resolveAddr(ctx, s.Net, addr)
+	if err != nil {
+		return sighting{}, err
+	}
+
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: 5 * time.Second}, Config: s.TLS}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(ip, strconv.Itoa(int(port))))
+	if err != nil {
+		return sighting{}, err
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return sighting{}, fmt.Errorf("peer presented no certificate")
+	}
+	sum := sha256.Sum256(certs[0].Raw)
+
+	// Static nodes have no TXT records. The controller fetches the
+	// fingerprint before adopting them.
+	c := Candidate{
+		Name:     certs[0].Subject.CommonName,
+		IP:       ip,
+		Port:     port,
+		Metadata: map[string]string{},
+	}
+	if host != ip {
+		c.Hostname = host
+	}
+
+	return sighting{Candidate: c, rev: hex.EncodeToString(sum[:])}, nil
+}
+
+// pollControllers reports every controller that accepts connections.
+func (s *Static) pollControllers(ctx context.Context) ([]sighting, error) {
+	var seen []sighting
+	for _, addr := range s.Controllers {
+		_, ip, port, err := resolveAddr(ctx, s.Net, addr)
+		if err != nil {
+			log.Debugf("Static controller %s is unresolvable: %v", addr, err)
+			continue
+		}
+
+		dialer := &net.Dialer{Timeout: 5 * time.Second}
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(ip, strconv.Itoa(int(port))))
+		if err != nil {
+			log.Debugf("Static controller %s is unreachable: %v", addr, err)
+			continue
+		}
+		conn.Close()
+
+		meta := parseMetadata(ClusterRecords(s.Cluster))
+		seen = append(seen, sighting{Candidate: Candidate{Name: addr, IP: ip, Port: port, Metadata: meta}})
+	}
+	return seen, nil
+}
+
+// resolveAddr splits a host:port address and resolves the host.
+func resolveAddr(ctx context.Context, sel *network.Selector, addr string) (host, ip string, port uint16, err error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", "", 0, err
+	}
+	p, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("invalid port in %s: %w", addr, err)
+	}
+	if ip, err = resolveHost(ctx, sel, host); err != nil {
+		return "", "", 0, err
+	}
+	return host, ip, uint16(p), nil
+}
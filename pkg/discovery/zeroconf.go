@@ -1,90 +1,155 @@
 package discovery
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"time"
+	"net"
+	"os"
+	"strings"
 
 	"github.com/lunarhue/libs-go/metadata"
 	zeroconf "github.com/lunarhue/metallic-flock-zeroconf"
+	"github.com/lunarhue/metallic-flock/pkg/network"
 )
 
 // Service Types
 var (
 	TypePending    = zeroconf.NewType("_hive-pending._tcp")
 	TypeController = zeroconf.NewType("_hive-controller._tcp")
+	// TypeMember is advertised by adopted nodes.
+	TypeMember = zeroconf.NewType("_hive-member._tcp")
 )
 
-// StartAgentBroadcast announces "I am here" (Pending) AND listens for Controllers.
-// Returns the client so you can Close() it later.
-func StartAgentBroadcast(id string, port uint16) (*zeroconf.Client, error) {
-	me := zeroconf.NewService(TypePending, id, port)
-	sysinfo, err := metadata.GetSystemInfo()
-	if err != nil {
-		me.Text = []string{"version=1.0", "error=" + err.Error()}
-		log.Printf("Failed to get system info: %v", err)
-	} else {
-		// Add useful metadata
-		me.Text = []string{
-			"version=1.0",
-			"cpu=" + fmt.Sprintf("%d", sysinfo.CPUCores),
-			"distro=" + sysinfo.Arch,
-			"ip=" + sysinfo.MainIP,
-			"mac=" + sysinfo.MainMAC,
-			"os=" + sysinfo.OS,
-			"disk=" + fmt.Sprintf("%f", sysinfo.TotalDiskGB),
-			"mem=" + fmt.Sprintf("%f", sysinfo.TotalMemoryGB),
-		}
+// ops maps zeroconf operations to ours. Expired services are reported as
+// removed.
+var ops = map[zeroconf.Op]Op{
+	zeroconf.OpAdded:   OpFound,
+	zeroconf.OpUpdated: OpUpdated,
+	zeroconf.OpRemoved: OpLost,
+}
+
+// Zeroconf discovers nodes over mDNS. It only reaches nodes on the same
+// broadcast domain.
+type Zeroconf struct {
+	// Net limits the interfaces and addresses used for mDNS and picks the
+	// address of peers that announce several.
+	Net *network.Selector
+}
+
+// client returns a zeroconf client limited to the selected interfaces and
+// addresses.
+func (z Zeroconf) client() *zeroconf.Client {
+	client := zeroconf.New()
+	if z.Net.Restricted() {
+		client = client.
+			Interfaces(z.Net.NetInterfaces).
+			InterfaceAddrs(z.Net.InterfaceAddrs)
 	}
+	return client
+}
 
-	client, err := zeroconf.New().
-		Publish(me).
+func (z Zeroconf) Advertise(svc *zeroconf.Service) (func(), error) {
+	client, err := z.client().
+		Publish(svc).
 		Open()
 
 	if err != nil {
 		return nil, err
 	}
 
-	return client, nil
+	return func() { client.Close() }, nil
 }
 
-// ScanForControllers looks for a controller for a specific duration.
-// It opens a temporary browser and closes it after the timeout.
-func ScanForControllers(duration time.Duration) string {
-	foundIP := make(chan string, 1)
-
-	// Define the browse callback
-	onEvent := func(e zeroconf.Event) {
-		// e.Op represents the operation (Added, Removed, etc)
-		// We only care if a service is Added (OpAdded is usually 0 or 1, assuming non-removal)
-		// The README example checks e.Op, but for simple discovery we just check if we have IPs.
-
-		if e.Op == zeroconf.OpAdded && len(e.Addrs) > 0 {
-			// We found a candidate
-			log.Printf("Discovered Controller: %s", e.Name)
-			select {
-			case foundIP <- e.Addrs[0].String():
-			default:
-			}
+func (z Zeroconf) Browse(ctx context.Context, typ zeroconf.Type, onEvent func(Event)) error {
+	onZeroconfEvent := func(e zeroconf.Event) {
+		op, ok := ops[e.Op]
+		if !ok {
+			return
 		}
+		// A service is of no use until its address is known.
+		if op != OpLost && len(e.Addrs) == 0 {
+			return
+		}
+		onEvent(Event{Op: op, Type: typ, Candidate: z.candidateFromEvent(e)})
 	}
 
-	// Start the browser
-	client, err := zeroconf.New().
-		Browse(onEvent, TypeController).
+	client, err := z.client().
+		Browse(onZeroconfEvent, typ).
 		Open()
 
 	if err != nil {
-		log.Printf("Failed to start scanner: %v", err)
-		return ""
+		return err
 	}
 	defer client.Close()
 
-	// Wait for result or timeout
-	select {
-	case ip := <-foundIP:
-		return ip
-	case <-time.After(duration):
-		return ""
+	<-ctx.Done()
+	return nil
+}
+
+// candidateFromEvent turns an mDNS announcement into a Candidate. The hostname
+// comes from the TXT records, falling back to the mDNS host name.
+func (z Zeroconf) candidateFromEvent(e zeroconf.Event) Candidate {
+	meta := parseMetadata(e.Text)
+
+	hostname := meta["hostname"]
+	if hostname == "" {
+		hostname = strings.TrimSuffix(e.Hostname, ".local")
+	}
+
+	c := Candidate{
+		Name:     e.Name,
+		Hostname: hostname,
+		Port:     e.Port,
+		Metadata: meta,
 	}
+	if ip := z.preferredAddr(e); ip != nil {
+		c.IP = ip.String()
+	}
+	return c
+}
+
+// preferredAddr picks the address to reach the announcing peer at.
+func (z Zeroconf) preferredAddr(e zeroconf.Event) net.IP {
+	addrs := make([]net.IP, len(e.Addrs))
+	for i, addr := range e.Addrs {
+		addrs[i] = addr.AsSlice()
+	}
+	return z.Net.Prefer(addrs)
+}
+
+// memberService describes this node as an adopted member of cluster.
+func memberService(id string, port uint16, cluster string) *zeroconf.Service {
+	me := zeroconf.NewService(TypeMember, id, port)
+	hostname, _ := os.Hostname()
+	me.Text = append([]string{"hostname=" + hostname}, ClusterRecords(cluster)...)
+	return me
+}
+
+// pendingService describes this node as a pending node, with a hardware
+// summary in its TXT records.
+func pendingService(id string, port uint16, extra []string) *zeroconf.Service {
+	me := zeroconf.NewService(TypePending, id, port)
+	hostname, _ := os.Hostname()
+	sysinfo, err := metadata.GetSystemInfo()
+	if err != nil {
+		me.Text = []string{"version=1.0", "hostname=" + hostname, "error=" + err.Error()}
+		log.Printf("Failed to get system info: %v", err)
+	} else {
+		// Add useful metadata
+		me.Text = []string{
+			"version=1.0",
+			"hostname=" + hostname,
+			"cpu=" + fmt.Sprintf("%d", sysinfo.CPUCores),
+			"distro=" + sysinfo.Arch,
+			"ip=" + sysinfo.MainIP,
+			"mac=" + sysinfo.MainMAC,
+			"os=" + sysinfo.OS,
+			"disk=" + fmt.Sprintf("%f", sysinfo.TotalDiskGB),
+			"mem=" + fmt.Sprintf("%f", sysinfo.TotalMemoryGB),
+		}
+	}
+	me.Text = append(me.Text, extra...)
+
+	return me
 }
@@ -0,0 +1,169 @@
+package fingerprint
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/lunarhue/libs-go/log"
+)
+
+// MemoryModuleInfo is a memory slot as SMBIOS describes it. SizeBytes is 0
+// for an empty slot.
+type MemoryModuleInfo struct {
+	Locator       string `json:"locator"`
+	BankLocator   string `json:"bank_locator,omitempty"`
+	SizeBytes     int64  `json:"size_bytes"`
+	Type          string `json:"type,omitempty"`
+	SpeedMTs      int    `json:"speed_mts,omitempty"`
+	ConfiguredMTs int    `json:"configured_mts,omitempty"`
+	Manufacturer  string `json:"manufacturer,omitempty"`
+	PartNumber    string `json:"part_number,omitempty"`
+	SerialNumber  string `json:"serial_number,omitempty"`
+}
+
+// smbiosMemoryDevice is the SMBIOS structure type of a memory slot.
+const smbiosMemoryDevice = 17
+
+// Offsets into the formatted area of a memory device structure, per the
+// SMBIOS specification (DSP0134).
+const (
+	dimmSize            = 0x0C
+	dimmDeviceLocator   = 0x10
+	dimmBankLocator     = 0x11
+	dimmMemoryType      = 0x12
+	dimmSpeed           = 0x15
+	dimmManufacturer    = 0x17
+	dimmSerialNumber    = 0x18
+	dimmPartNumber      = 0x1A
+	dimmExtendedSize    = 0x1C
+	dimmConfiguredSpeed = 0x20
+	dimmExtendedSpeed   = 0x54
+)
+
+var dimmTypes = map[byte]string{
+	0x0F: "SDRAM",
+	0x12: "DDR",
+	0x13: "DDR2",
+	0x18: "DDR3",
+	0x1A: "DDR4",
+	0x1B: "LPDDR",
+	0x1C: "LPDDR2",
+	0x1D: "LPDDR3",
+	0x1E: "LPDDR4",
+	0x1F: "Logical non-volatile device",
+	0x20: "HBM",
+	0x21: "HBM2",
+	0x22: "DDR5",
+	0x23: "LPDDR5",
+	0x24: "HBM3",
+}
+
+// GetMemoryModules reads the memory slots from the raw SMBIOS tables in
+// /sys/firmware/dmi/entries. They are only readable by root; without them,
+// or on machines without SMBIOS, it returns nothing. Entries that can't be
+// read are skipped.
+func GetMemoryModules() []MemoryModuleInfo {
+	dir := hostPath("sys/firmware/dmi/entries")
+	if dir == "" {
+		return nil
+	}
+
+	entries, err := filepath.Glob(filepath.Join(dir, "17-*", "raw"))
+	if err != nil {
+		return nil
+	}
+	// Entries are numbered in table order, 17-0 to 17-N; sort 17-10 after
+	// 17-9.
+	slices.SortFunc(entries, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(a), len(b)), strings.Compare(a, b))
+	})
+
+	var modules []MemoryModuleInfo
+	for _, entry := range entries {
+		raw, err := os.ReadFile(entry)
+		if err != nil {
+			log.Debugf("Unable to read SMBIOS entry %s: %v", entry, err)
+			continue
+		}
+		if module, ok := parseMemoryDevice(raw); ok {
+			modules = append(modules, module)
+		}
+	}
+
+	return modules
+}
+
+// parseMemoryDevice decodes a type 17 structure: a formatted area whose
+// length is in its second byte, followed by the strings it refers to by
+// 1-based index.
+func parseMemoryDevice(raw []byte) (MemoryModuleInfo, bool) {
+	if len(raw) < 2 || raw[0] != smbiosMemoryDevice {
+		return MemoryModuleInfo{}, false
+	}
+	length := int(raw[1])
+	if length <= dimmMemoryType || len(raw) < length {
+		return MemoryModuleInfo{}, false
+	}
+	formatted := raw[:length]
+	strs := bytes.Split(raw[length:], []byte{0})
+
+	str := func(offset int) string {
+		if offset >= length {
+			return ""
+		}
+		i := int(formatted[offset])
+		if i == 0 || i > len(strs) {
+			return ""
+		}
+		return dmiValue(string(strs[i-1]))
+	}
+	word := func(offset int) int {
+		if offset+2 > length {
+			return 0
+		}
+		return int(binary.LittleEndian.Uint16(formatted[offset:]))
+	}
+
+	module := MemoryModuleInfo{
+		Locator:      str(dimmDeviceLocator),
+		BankLocator:  str(dimmBankLocator),
+		Type:         dimmTypes[formatted[dimmMemoryType]],
+		Manufacturer: str(dimmManufacturer),
+		PartNumber:   str(dimmPartNumber),
+		SerialNumber: str(dimmSerialNumber),
+	}
+
+	// The size is in MB, or in KB if the top bit is set. 0x7FFF means it
+	// didn't fit and is in the extended size field, 0xFFFF that it's unknown.
+	switch size := word(dimmSize); {
+	case size == 0:
+		// Empty slots carry placeholders such as "NO DIMM" in the other
+		// fields.
+		return MemoryModuleInfo{Locator: module.Locator, BankLocator: module.BankLocator}, true
+	case size == 0xFFFF:
+	case size == 0x7FFF && dimmExtendedSize+4 <= length:
+		mb := binary.LittleEndian.Uint32(formatted[dimmExtendedSize:]) & 0x7FFFFFFF
+		module.SizeBytes = int64(mb) << 20
+	case size&0x8000 != 0:
+		module.SizeBytes = int64(size&0x7FFF) << 10
+	default:
+		module.SizeBytes = int64(size) << 20
+	}
+
+	// Speeds beyond 65534 MT/s are in the extended speed field.
+	if speed := word(dimmSpeed); speed != 0xFFFF {
+		module.SpeedMTs = speed
+	} else if dimmExtendedSpeed+4 <= length {
+		module.SpeedMTs = int(binary.LittleEndian.Uint32(formatted[dimmExtendedSpeed:]) & 0x7FFFFFFF)
+	}
+	if configured := word(dimmConfiguredSpeed); configured != 0xFFFF {
+		module.ConfiguredMTs = configured
+	}
+
+	return module, true
+}
@@ -0,0 +1,146 @@
+package fingerprint
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// memoryDevice builds a raw SMBIOS type 17 structure with a formatted area of
+// length bytes, filled in by set, followed by strs.
+func memoryDevice(length int, set func(f []byte), strs ...string) []byte {
+	formatted := make([]byte, length)
+	formatted[0] = smbiosMemoryDevice
+	formatted[1] = byte(length)
+	if set != nil {
+		set(formatted)
+	}
+
+	raw := formatted
+	for _, s := range strs {
+		raw = append(append(raw, s...), 0)
+	}
+	return append(raw, 0)
+}
+
+// dimmStrings points the string fields of f at the strings memoryDevice
+// appends: locator, bank locator, manufacturer, serial and part number.
+func dimmStrings(f []byte) {
+	f[dimmDeviceLocator] = 1
+	f[dimmBankLocator] = 2
+	f[dimmManufacturer] = 3
+	f[dimmSerialNumber] = 4
+	f[dimmPartNumber] = 5
+}
+
+func TestParseMemoryDevice(t *testing.T) {
+	strs := []string{"DIMM_A1", "BANK 0", "Samsung", "12345678", "M393A2K43DB3-CWE "}
+
+	tests := []struct {
+		name   string
+		raw    []byte
+		want   MemoryModuleInfo
+		wantOk bool
+	}{
+		{
+			name: "ddr4 module",
+			raw: memoryDevice(0x28, func(f []byte) {
+				dimmStrings(f)
+				binary.LittleEndian.PutUint16(f[dimmSize:], 16384)
+				f[dimmMemoryType] = 0x1A
+				binary.LittleEndian.PutUint16(f[dimmSpeed:], 3200)
+				binary.LittleEndian.PutUint16(f[dimmConfiguredSpeed:], 2933)
+			}, strs...),
+			want: MemoryModuleInfo{
+				Locator:       "DIMM_A1",
+				BankLocator:   "BANK 0",
+				SizeBytes:     16 << 30,
+				Type:          "DDR4",
+				SpeedMTs:      3200,
+				ConfiguredMTs: 2933,
+				Manufacturer:  "Samsung",
+				PartNumber:    "M393A2K43DB3-CWE",
+				SerialNumber:  "12345678",
+			},
+			wantOk: true,
+		},
+		{
+			name: "unpopulated slot",
+			raw: memoryDevice(0x28, func(f []byte) {
+				dimmStrings(f)
+				f[dimmMemoryType] = 0x02
+				binary.LittleEndian.PutUint16(f[dimmSpeed:], 0xFFFF)
+			}, "DIMM_B1", "BANK 1", "NO DIMM", "NO DIMM", "NO DIMM"),
+			want:   MemoryModuleInfo{Locator: "DIMM_B1", BankLocator: "BANK 1"},
+			wantOk: true,
+		},
+		{
+			name: "extended size",
+			raw: memoryDevice(0x28, func(f []byte) {
+				dimmStrings(f)
+				binary.LittleEndian.PutUint16(f[dimmSize:], 0x7FFF)
+				binary.LittleEndian.PutUint32(f[dimmExtendedSize:], 65536)
+				f[dimmMemoryType] = 0x22
+				binary.LittleEndian.PutUint16(f[dimmSpeed:], 4800)
+			}, strs...),
+			want: MemoryModuleInfo{
+				Locator:      "DIMM_A1",
+				BankLocator:  "BANK 0",
+				SizeBytes:    64 << 30,
+				Type:         "DDR5",
+				SpeedMTs:     4800,
+				Manufacturer: "Samsung",
+				PartNumber:   "M393A2K43DB3-CWE",
+				SerialNumber: "12345678",
+			},
+			wantOk: true,
+		},
+		{
+			name: "size in KB and extended speed",
+			raw: memoryDevice(0x5C, func(f []byte) {
+				f[dimmDeviceLocator] = 1
+				binary.LittleEndian.PutUint16(f[dimmSize:], 0x8000|512)
+				f[dimmMemoryType] = 0x23
+				binary.LittleEndian.PutUint16(f[dimmSpeed:], 0xFFFF)
+				binary.LittleEndian.PutUint32(f[dimmExtendedSpeed:], 70000)
+				binary.LittleEndian.PutUint16(f[dimmConfiguredSpeed:], 0xFFFF)
+			}, "Onboard"),
+			want: MemoryModuleInfo{
+				Locator:   "Onboard",
+				SizeBytes: 512 << 10,
+				Type:      "LPDDR5",
+				SpeedMTs:  70000,
+			},
+			wantOk: true,
+		},
+		{
+			name: "short record",
+			raw:  memoryDevice(0x0F, nil),
+		},
+		{
+			name: "truncated record",
+			raw:  memoryDevice(0x28, nil)[:0x20],
+		},
+		{
+			name: "other structure type",
+			raw: memoryDevice(0x28, func(f []byte) {
+				f[0] = 16
+			}),
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseMemoryDevice(tt.raw)
+			if ok != tt.wantOk {
+				t.Fatalf("parseMemoryDevice() ok = %t, want %t", ok, tt.wantOk)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseMemoryDevice() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
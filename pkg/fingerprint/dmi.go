@@ -0,0 +1,100 @@
+package fingerprint
+
+import (
+	"strings"
+
+	"github.com/jaypipes/ghw"
+	"github.com/lunarhue/libs-go/log"
+)
+
+// DmiInfo is the machine's identity from SMBIOS, as the kernel exposes it in
+// /sys/class/dmi/id. Serials and the UUID are only readable by root; fields
+// that can't be read or that the vendor left as a placeholder are empty.
+type DmiInfo struct {
+	Vendor          string `json:"vendor,omitempty"`
+	Product         string `json:"product,omitempty"`
+	SerialNumber    string `json:"serial_number,omitempty"`
+	UUID            string `json:"uuid,omitempty"`
+	BoardVendor     string `json:"board_vendor,omitempty"`
+	BoardProduct    string `json:"board_product,omitempty"`
+	BoardSerial     string `json:"board_serial,omitempty"`
+	ChassisVendor   string `json:"chassis_vendor,omitempty"`
+	ChassisType     string `json:"chassis_type,omitempty"`
+	ChassisSerial   string `json:"chassis_serial,omitempty"`
+	ChassisAssetTag string `json:"chassis_asset_tag,omitempty"`
+	BiosVendor      string `json:"bios_vendor,omitempty"`
+	BiosVersion     string `json:"bios_version,omitempty"`
+	BiosDate        string `json:"bios_date,omitempty"`
+}
+
+// dmiPlaceholders are values firmware ships with when the vendor didn't fill
+// a field in. They identify nothing.
+var dmiPlaceholders = []string{
+	"unknown",
+	"to be filled by o.e.m.",
+	"default string",
+	"not specified",
+	"not applicable",
+	"system serial number",
+	"system product name",
+	"system manufacturer",
+	"none",
+	"0123456789",
+	"03000200-0400-0500-0006-000700080009",
+}
+
+// GetDmiInfo reads the machine's SMBIOS identity. It never fails: machines
+// without DMI, such as most ARM boards, and unprivileged runs just leave
+// fields empty.
+func GetDmiInfo() DmiInfo {
+	// ghw warns about every unreadable field, which is expected without root.
+	quiet := ghw.WithNullAlerter()
+
+	var info DmiInfo
+
+	if product, err := ghw.Product(quiet); err != nil {
+		log.Debugf("Unable to read DMI product info: %v", err)
+	} else {
+		info.Vendor = dmiValue(product.Vendor)
+		info.Product = dmiValue(product.Name)
+		info.SerialNumber = dmiValue(product.SerialNumber)
+		info.UUID = dmiValue(product.UUID)
+	}
+
+	if board, err := ghw.Baseboard(quiet); err != nil {
+		log.Debugf("Unable to read DMI baseboard info: %v", err)
+	} else {
+		info.BoardVendor = dmiValue(board.Vendor)
+		info.BoardProduct = dmiValue(board.Product)
+		info.BoardSerial = dmiValue(board.SerialNumber)
+	}
+
+	if chassis, err := ghw.Chassis(quiet); err != nil {
+		log.Debugf("Unable to read DMI chassis info: %v", err)
+	} else {
+		info.ChassisVendor = dmiValue(chassis.Vendor)
+		info.ChassisType = dmiValue(chassis.TypeDescription)
+		info.ChassisSerial = dmiValue(chassis.SerialNumber)
+		info.ChassisAssetTag = dmiValue(chassis.AssetTag)
+	}
+
+	if bios, err := ghw.BIOS(quiet); err != nil {
+		log.Debugf("Unable to read DMI BIOS info: %v", err)
+	} else {
+		info.BiosVendor = dmiValue(bios.Vendor)
+		info.BiosVersion = dmiValue(bios.Version)
+		info.BiosDate = dmiValue(bios.Date)
+	}
+
+	return info
+}
+
+func dmiValue(value string) string {
+	value = strings.TrimSpace(value)
+	for _, placeholder := range dmiPlaceholders {
+		if strings.EqualFold(value, placeholder) {
+			return ""
+		}
+	}
+	return value
+}
@@ -3,6 +3,9 @@ package fingerprint
 import (
 	_ "embed"
 	"fmt"
+	"path/filepath"
+
+	"github.com/jaypipes/ghw/pkg/option"
 )
 
 type Fingerprint struct {
@@ -11,6 +14,8 @@ type Fingerprint struct {
 	Memory  MemoryInfo             `json:"memory"`
 	Network []NetworkInterfaceInfo `json:"network"`
 	Storage []StorageInfo          `json:"storage"`
+	Gpus    []GpuInfo              `json:"gpus"`
+	Numa    []NumaNodeInfo         `json:"numa"`
 }
 
 func GetFingerprint() (Fingerprint, error) {
@@ -39,13 +44,42 @@ func GetFingerprint() (Fingerprint, error) {
 		return Fingerprint{}, fmt.Errorf("failed to get storage devices: %v", err)
 	}
 
+	gpus, err := GetGpus()
+	if err != nil {
+		return Fingerprint{}, fmt.Errorf("failed to get gpus: %v", err)
+	}
+
+	numaNodes, err := GetNumaNodes()
+	if err != nil {
+		return Fingerprint{}, fmt.Errorf("failed to get numa nodes: %v", err)
+	}
+
 	fingerprint := Fingerprint{
 		System:  systemInfo,
 		Cpus:    cpus,
 		Memory:  memoryInfo,
 		Network: networkInterfaces,
 		Storage: storageDevices,
+		Gpus:    gpus,
+		Numa:    numaNodes,
 	}
 
 	return fingerprint, nil
 }
+
+// hostPath joins elem under the root ghw reads hardware from, GHW_CHROOT or /,
+// for the sysfs files ghw doesn't parse. It returns an empty string when
+// reading from a ghw snapshot, whose unpacked location isn't known outside
+// ghw.
+func hostPath(elem ...string) string {
+	if option.EnvOrDefaultSnapshotPath() != "" {
+		return ""
+	}
+	return filepath.Join(append([]string{option.EnvOrDefaultChroot()}, elem...)...)
+}
+
+// onLiveHost reports whether ghw reads the hardware of the machine it runs
+// on, so tools that query devices directly describe the same hardware.
+func onLiveHost() bool {
+	return option.EnvOrDefaultSnapshotPath() == "" && option.EnvOrDefaultChroot() == option.DefaultChroot
+}
@@ -0,0 +1,124 @@
+package fingerprint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jaypipes/ghw"
+	"github.com/jaypipes/ghw/pkg/pci"
+)
+
+// Kinds of GpuInfo.
+const (
+	GpuKindGpu         = "gpu"
+	GpuKindAccelerator = "accelerator"
+)
+
+type GpuInfo struct {
+	// Kind is gpu for cards driving a display and accelerator for
+	// processing accelerators and 3D controllers without one.
+	Kind       string `json:"kind"`
+	Vendor     string `json:"vendor"`
+	Model      string `json:"model"`
+	PciAddress string `json:"pci_address"`
+	Driver     string `json:"driver"`
+	// VramBytes is 0 if the driver doesn't expose the card's memory in
+	// sysfs, which the proprietary NVIDIA driver doesn't.
+	VramBytes int64 `json:"vram_bytes,omitempty"`
+}
+
+// GetGpus lists the node's GPUs and accelerators. Like every other section it
+// honors GHW_CHROOT and GHW_SNAPSHOT_PATH, so it can be read from a fixture.
+func GetGpus() ([]GpuInfo, error) {
+	gpu, err := ghw.GPU()
+	if err != nil {
+		return nil, fmt.Errorf("error getting gpu info: %v", err)
+	}
+
+	accelerator, err := ghw.Accelerator()
+	if err != nil {
+		return nil, fmt.Errorf("error getting accelerator info: %v", err)
+	}
+
+	var gpus []GpuInfo
+	for _, card := range gpu.GraphicsCards {
+		gpus = append(gpus, newGpuInfo(GpuKindGpu, card.Address, card.DeviceInfo))
+	}
+
+	// Display and 3D controllers show up in both lists.
+	for _, dev := range accelerator.Devices {
+		if slices.ContainsFunc(gpus, func(g GpuInfo) bool { return g.PciAddress == dev.Address }) {
+			continue
+		}
+		gpus = append(gpus, newGpuInfo(GpuKindAccelerator, dev.Address, dev.PCIDevice))
+	}
+
+	return gpus, nil
+}
+
+func newGpuInfo(kind, address string, dev *pci.Device) GpuInfo {
+	info := GpuInfo{
+		Kind:       kind,
+		PciAddress: address,
+	}
+
+	if dev != nil {
+		info.Driver = dev.Driver
+		if dev.Vendor != nil {
+			info.Vendor = dev.Vendor.Name
+		}
+		if dev.Product != nil {
+			info.Model = dev.Product.Name
+		}
+	}
+
+	// Without a PCI ID database, which minimal images often lack, ghw knows
+	// nothing but the address. Fall back to the raw IDs and the driver link.
+	device := hostPath("sys/bus/pci/devices", address)
+	if device == "" {
+		return info
+	}
+	if info.Vendor == "" {
+		info.Vendor = readSysfs(filepath.Join(device, "vendor"))
+	}
+	if info.Model == "" {
+		info.Model = readSysfs(filepath.Join(device, "device"))
+	}
+	if info.Driver == "" {
+		if driver, err := os.Readlink(filepath.Join(device, "driver")); err == nil {
+			info.Driver = filepath.Base(driver)
+		}
+	}
+	info.VramBytes = getVramBytes(device)
+
+	return info
+}
+
+// getVramBytes reads the card's dedicated memory from sysfs. amdgpu exposes it
+// on the PCI device, i915 on the DRM card.
+func getVramBytes(device string) int64 {
+	paths := []string{filepath.Join(device, "mem_info_vram_total")}
+	if cards, err := filepath.Glob(filepath.Join(device, "drm/card*/lmem_total_bytes")); err == nil {
+		paths = append(paths, cards...)
+	}
+
+	for _, path := range paths {
+		if size, err := strconv.ParseInt(readSysfs(path), 10, 64); err == nil {
+			return size
+		}
+	}
+
+	return 0
+}
+
+func readSysfs(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
@@ -0,0 +1,86 @@
+package fingerprint
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+// The fixtures in testdata can be read with `metallic debug fingerprint
+// --chroot` and --snapshot as well. gpu has an amdgpu card, a Gaudi2
+// accelerator and a NIC; no-gpu only the NIC. gpu.tar.gz is gpu's sys and proc
+// as a ghw snapshot.
+func TestGetGpus(t *testing.T) {
+	radeon := GpuInfo{
+		Kind:       GpuKindGpu,
+		Vendor:     "Advanced Micro Devices, Inc. [AMD/ATI]",
+		Model:      "Navi 31 [Radeon RX 7900 XT/7900 XTX/7900 GRE/7900M]",
+		PciAddress: "0000:03:00.0",
+		Driver:     "amdgpu",
+		VramBytes:  25753026560,
+	}
+	gaudi := GpuInfo{
+		Kind:       GpuKindAccelerator,
+		Vendor:     "Habana Labs Ltd.",
+		Model:      "Gaudi2 AI Training Accelerator",
+		PciAddress: "0000:41:00.0",
+		Driver:     "habanalabs",
+	}
+	// A snapshot holds what ghw reads, which doesn't include the VRAM.
+	snapshotRadeon := radeon
+	snapshotRadeon.VramBytes = 0
+
+	tests := []struct {
+		name     string
+		chroot   string
+		snapshot string
+		want     []GpuInfo
+	}{
+		{
+			name:   "gpu and accelerator",
+			chroot: "testdata/gpu",
+			want:   []GpuInfo{radeon, gaudi},
+		},
+		{
+			name:   "no gpu",
+			chroot: "testdata/no-gpu",
+		},
+		{
+			name:     "snapshot",
+			snapshot: "testdata/gpu.tar.gz",
+			want:     []GpuInfo{snapshotRadeon, gaudi},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GHW_DISABLE_WARNINGS", "1")
+			setenv(t, "GHW_CHROOT", tt.chroot)
+			setenv(t, "GHW_SNAPSHOT_PATH", tt.snapshot)
+			// ghw doesn't hand snapshots to the PCI ID database, so point
+			// it at the fixture's rather than whatever the host has.
+			pcidb := ""
+			if tt.snapshot != "" {
+				pcidb = "testdata/gpu/usr/share/hwdata/pci.ids"
+			}
+			setenv(t, "PCIDB_PATH", pcidb)
+
+			got, err := GetGpus()
+			if err != nil {
+				t.Fatalf("GetGpus() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetGpus() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// setenv sets key for the test, or unsets it if value is empty: ghw takes an
+// empty GHW_CHROOT for a chroot, which conflicts with a snapshot.
+func setenv(t *testing.T, key, value string) {
+	t.Setenv(key, value)
+	if value == "" {
+		os.Unsetenv(key)
+	}
+}
@@ -0,0 +1,103 @@
+package fingerprint
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of virtual interfaces a NIC can be part of, as the kernel names them
+// in DEVTYPE.
+const (
+	LinkBond   = "bond"
+	LinkBridge = "bridge"
+	LinkVlan   = "vlan"
+)
+
+// readLinkInfo fills in nic's link details from /sys/class/net. Values the
+// kernel doesn't report, e.g. the speed of a link that is down, stay empty.
+func readLinkInfo(nic *NetworkInterfaceInfo) {
+	dir := hostPath("sys/class/net", nic.InterfaceName)
+	if dir == "" {
+		return
+	}
+
+	nic.LinkState = readSysfs(filepath.Join(dir, "operstate"))
+	if speed, err := strconv.Atoi(readSysfs(filepath.Join(dir, "speed"))); err == nil && speed > 0 {
+		nic.SpeedMbps = speed
+	}
+	if duplex := readSysfs(filepath.Join(dir, "duplex")); duplex != "unknown" {
+		nic.Duplex = duplex
+	}
+	nic.Mtu, _ = strconv.Atoi(readSysfs(filepath.Join(dir, "mtu")))
+
+	if driver, err := os.Readlink(filepath.Join(dir, "device/driver")); err == nil {
+		nic.Driver = filepath.Base(driver)
+	}
+	nic.SriovTotalVfs, _ = strconv.Atoi(readSysfs(filepath.Join(dir, "device/sriov_totalvfs")))
+	nic.SriovNumVfs, _ = strconv.Atoi(readSysfs(filepath.Join(dir, "device/sriov_numvfs")))
+
+	// A bond or bridge the NIC is enslaved to is its master. VLANs on top of
+	// it are upper devices too, as is the master itself.
+	if master, err := os.Readlink(filepath.Join(dir, "master")); err == nil {
+		nic.Master = filepath.Base(master)
+		nic.MasterType = linkType(nic.Master)
+	}
+	uppers, _ := filepath.Glob(filepath.Join(dir, "upper_*"))
+	for _, upper := range uppers {
+		name := strings.TrimPrefix(filepath.Base(upper), "upper_")
+		if name != nic.Master && linkType(name) == LinkVlan {
+			nic.Vlans = append(nic.Vlans, name)
+		}
+	}
+
+	nic.Firmware = getFirmwareVersion(nic.InterfaceName)
+}
+
+// linkType returns the DEVTYPE of the interface name, empty for plain
+// Ethernet devices.
+func linkType(name string) string {
+	uevent := readSysfs(hostPath("sys/class/net", name, "uevent"))
+	for _, line := range strings.Split(uevent, "\n") {
+		if devType, ok := strings.CutPrefix(line, "DEVTYPE="); ok {
+			return devType
+		}
+	}
+	return ""
+}
+
+// getFirmwareVersion asks ethtool for the NIC's firmware version, which
+// sysfs doesn't expose. It is empty if ethtool isn't installed or the driver
+// doesn't report one.
+func getFirmwareVersion(name string) string {
+	if !onLiveHost() {
+		return ""
+	}
+
+	path, err := exec.LookPath("ethtool")
+	if err != nil {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, path, "-i", name).Output()
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if version, ok := strings.CutPrefix(line, "firmware-version:"); ok {
+			version = strings.TrimSpace(version)
+			if version == "N/A" {
+				return ""
+			}
+			return version
+		}
+	}
+	return ""
+}
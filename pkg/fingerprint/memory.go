@@ -1,13 +1,29 @@
 package fingerprint
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 
 	"github.com/jaypipes/ghw"
 )
 
 type MemoryInfo struct {
 	TotalBytes int64 `json:"total_bytes"`
+	// DefaultHugePageBytes is the size of the huge pages the kernel hands
+	// out when none is asked for.
+	DefaultHugePageBytes int64          `json:"default_huge_page_bytes,omitempty"`
+	HugePages            []HugePageInfo `json:"huge_pages,omitempty"`
+	// Modules lists the memory slots SMBIOS describes, empty ones included,
+	// so half-populated channels show up. Reading them needs root.
+	Modules []MemoryModuleInfo `json:"modules,omitempty"`
+}
+
+// HugePageInfo is the pool of huge pages of one size.
+type HugePageInfo struct {
+	SizeBytes int64 `json:"size_bytes"`
+	Total     int64 `json:"total"`
+	Free      int64 `json:"free"`
 }
 
 func GetMemoryInfo() (MemoryInfo, error) {
@@ -16,7 +32,22 @@ func GetMemoryInfo() (MemoryInfo, error) {
 		return MemoryInfo{}, fmt.Errorf("error getting memory info: %v", err)
 	}
 
+	var hugePages []HugePageInfo
+	for size, amounts := range mem.HugePageAmountsBySize {
+		hugePages = append(hugePages, HugePageInfo{
+			SizeBytes: int64(size),
+			Total:     amounts.Total,
+			Free:      amounts.Free,
+		})
+	}
+	slices.SortFunc(hugePages, func(a, b HugePageInfo) int {
+		return cmp.Compare(a.SizeBytes, b.SizeBytes)
+	})
+
 	return MemoryInfo{
-		TotalBytes: mem.TotalUsableBytes,
+		TotalBytes:           mem.TotalUsableBytes,
+		DefaultHugePageBytes: int64(mem.DefaultHugePageSize),
+		HugePages:            hugePages,
+		Modules:              GetMemoryModules(),
 	}, nil
 }
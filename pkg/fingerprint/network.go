@@ -14,6 +14,27 @@ type NetworkInterfaceInfo struct {
 	MacAddress    string `json:"mac_address"`
 	Vendor        string `json:"vendor"`
 	CurrentIp     string `json:"current_ip"`
+	CurrentIpv6   string `json:"current_ipv6,omitempty"`
+	// Addresses are all of the NIC's addresses in CIDR notation, link-local
+	// ones included.
+	Addresses  []string `json:"addresses,omitempty"`
+	PciAddress string   `json:"pci_address,omitempty"`
+	Driver     string   `json:"driver,omitempty"`
+	Firmware   string   `json:"firmware,omitempty"`
+	// LinkState is the kernel's operational state, e.g. up, down or dormant.
+	LinkState string `json:"link_state,omitempty"`
+	SpeedMbps int    `json:"speed_mbps,omitempty"`
+	Duplex    string `json:"duplex,omitempty"`
+	Mtu       int    `json:"mtu,omitempty"`
+	// Master is the bond or bridge the NIC is part of, MasterType which of
+	// the two it is.
+	Master     string   `json:"master,omitempty"`
+	MasterType string   `json:"master_type,omitempty"`
+	Vlans      []string `json:"vlans,omitempty"`
+	// SriovTotalVfs is the number of SR-IOV virtual functions the NIC
+	// supports, 0 if it doesn't. SriovNumVfs are enabled.
+	SriovTotalVfs int `json:"sriov_total_vfs,omitempty"`
+	SriovNumVfs   int `json:"sriov_num_vfs,omitempty"`
 }
 
 func GetNetworkInterfaces() ([]NetworkInterfaceInfo, error) {
@@ -40,31 +61,69 @@ func GetNetworkInterfaces() ([]NetworkInterfaceInfo, error) {
 			log.Warnf("Unable to get Vendor for MAC Address: %s, %v", nic.MACAddress, err)
 		}
 
-		var currentIp string
-		for _, iface := range osInterfaces {
-			if strings.EqualFold(iface.HardwareAddr.String(), nic.MACAddress) {
-				addrs, err := iface.Addrs()
-				if err == nil {
-					for _, addr := range addrs {
-						if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() {
-							if ipNet.IP.To4() != nil {
-								currentIp = ipNet.IP.String()
-								break
-							}
+		// Link-local IPv6 addresses exist on every interface and don't say
+		// anything about how the node is reachable.
+		var currentIp, currentIpv6 string
+		var addresses []string
+		if iface := osInterface(osInterfaces, nic.Name, nic.MACAddress); iface != nil {
+			addrs, err := iface.Addrs()
+			if err == nil {
+				for _, addr := range addrs {
+					ipNet, ok := addr.(*net.IPNet)
+					if !ok {
+						continue
+					}
+					addresses = append(addresses, ipNet.String())
+					if ipNet.IP.IsLoopback() || ipNet.IP.IsLinkLocalUnicast() {
+						continue
+					}
+					if ipNet.IP.To4() != nil {
+						if currentIp == "" {
+							currentIp = ipNet.IP.String()
 						}
+					} else if currentIpv6 == "" {
+						currentIpv6 = ipNet.IP.String()
 					}
 				}
-				break
 			}
 		}
 
-		interfaces = append(interfaces, NetworkInterfaceInfo{
+		info := NetworkInterfaceInfo{
 			InterfaceName: nic.Name,
 			MacAddress:    nic.MACAddress,
 			Vendor:        vendor,
 			CurrentIp:     currentIp,
-		})
+			CurrentIpv6:   currentIpv6,
+			Addresses:     addresses,
+		}
+		if nic.PCIAddress != nil {
+			info.PciAddress = *nic.PCIAddress
+		}
+		readLinkInfo(&info)
+
+		interfaces = append(interfaces, info)
 	}
 
 	return interfaces, nil
 }
+
+// osInterface finds the OS interface of the NIC name. Bond slaves, bridge
+// ports and VLANs share their MAC address with other interfaces, so the MAC
+// is only used if no interface has the name, e.g. because it was renamed
+// since ghw read it.
+func osInterface(interfaces []net.Interface, name, mac string) *net.Interface {
+	for i := range interfaces {
+		if interfaces[i].Name == name {
+			return &interfaces[i]
+		}
+	}
+	if mac == "" {
+		return nil
+	}
+	for i := range interfaces {
+		if strings.EqualFold(interfaces[i].HardwareAddr.String(), mac) {
+			return &interfaces[i]
+		}
+	}
+	return nil
+}
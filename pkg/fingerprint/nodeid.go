@@ -0,0 +1,145 @@
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jaypipes/ghw"
+	"github.com/jaypipes/ghw/pkg/block"
+)
+
+// nodeIDPrefix keeps node IDs valid Kubernetes node names even when the hash
+// starts with a digit.
+const nodeIDPrefix = "node-"
+
+// placeholderUUIDs are SMBIOS UUIDs vendors ship on every board instead of a
+// unique one.
+var placeholderUUIDs = []string{
+	"00000000-0000-0000-0000-000000000000",
+	"ffffffff-ffff-ffff-ffff-ffffffffffff",
+	"03000200-0400-0500-0006-000700080009",
+}
+
+// NodeID derives a stable ID from hardware that survives reimaging: the SMBIOS
+// product UUID, the lowest MAC address of the physical NICs and the serial
+// numbers of the fixed disks. The result is a valid Kubernetes node name.
+func NodeID() (string, error) {
+	var parts []string
+
+	if uuid := productUUID(); uuid != "" {
+		parts = append(parts, "uuid="+uuid)
+	}
+	if mac := primaryMAC(); mac != "" {
+		parts = append(parts, "mac="+mac)
+	}
+	for _, serial := range diskSerials() {
+		parts = append(parts, "disk="+serial)
+	}
+
+	// Without any stable hardware identifier fall back to the machine ID,
+	// which at least stays the same for the lifetime of the installation.
+	if len(parts) == 0 {
+		machineID, err := os.ReadFile("/etc/machine-id")
+		if err != nil {
+			return "", fmt.Errorf("no stable hardware identifiers and no machine id: %v", err)
+		}
+		parts = append(parts, "machine-id="+strings.TrimSpace(string(machineID)))
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return nodeIDPrefix + hex.EncodeToString(sum[:])[:12], nil
+}
+
+// LoadNodeID returns the node ID stored at path, deriving and storing it on
+// first use. Keeping it on disk means replacing a disk or NIC later doesn't
+// turn the node into a stranger to the controller.
+func LoadNodeID(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if id := strings.TrimSpace(string(data)); id != "" {
+			return id, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("failed to read node id: %w", err)
+	}
+
+	id, err := NodeID()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create node id directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(id+"\n"), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write node id: %w", err)
+	}
+
+	return id, nil
+}
+
+func productUUID() string {
+	product, err := ghw.Product()
+	if err != nil {
+		return ""
+	}
+
+	uuid := strings.ToLower(strings.TrimSpace(product.UUID))
+	if uuid == "" || uuid == "unknown" || slices.Contains(placeholderUUIDs, uuid) {
+		return ""
+	}
+	return uuid
+}
+
+// primaryMAC picks the lowest MAC address so the choice doesn't depend on
+// interface naming or enumeration order.
+func primaryMAC() string {
+	netInfo, err := ghw.Network()
+	if err != nil {
+		return ""
+	}
+
+	var macs []string
+	for _, nic := range netInfo.NICs {
+		if nic.IsVirtual || nic.MACAddress == "" {
+			continue
+		}
+		macs = append(macs, strings.ToLower(nic.MACAddress))
+	}
+	if len(macs) == 0 {
+		return ""
+	}
+
+	return slices.Min(macs)
+}
+
+// diskSerials returns the sorted serial numbers of non-removable, physical
+// disks. USB sticks and loop devices come and go.
+func diskSerials() []string {
+	blockInfo, err := ghw.Block()
+	if err != nil {
+		return nil
+	}
+
+	var serials []string
+	for _, disk := range blockInfo.Disks {
+		if disk.IsRemovable || disk.StorageController == block.StorageControllerLoop {
+			continue
+		}
+
+		serial := strings.TrimSpace(disk.SerialNumber)
+		if serial == "" || strings.EqualFold(serial, "unknown") {
+			continue
+		}
+		serials = append(serials, serial)
+	}
+
+	slices.Sort(serials)
+	return serials
+}
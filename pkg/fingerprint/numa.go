@@ -0,0 +1,83 @@
+package fingerprint
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jaypipes/ghw"
+)
+
+type NumaNodeInfo struct {
+	ID int `json:"id"`
+	// Cpus are the logical processors of the node.
+	Cpus        []int `json:"cpus"`
+	MemoryBytes int64 `json:"memory_bytes"`
+	// Distances are the relative costs of reaching each node's memory from
+	// this one, indexed by node ID. 10 is local.
+	Distances []int       `json:"distances,omitempty"`
+	Caches    []CacheInfo `json:"caches,omitempty"`
+}
+
+// CacheInfo describes the caches of one level and type on a NUMA node.
+type CacheInfo struct {
+	Level int `json:"level"`
+	// Type is data, instruction or unified.
+	Type      string `json:"type"`
+	SizeBytes int64  `json:"size_bytes"`
+	// Count is the number of caches of this kind on the node, e.g. one L2
+	// per core.
+	Count int `json:"count"`
+}
+
+// GetNumaNodes lists the machine's NUMA nodes. Machines that aren't NUMA
+// usually have a single node holding everything. Kernels built without NUMA
+// support have no /sys/devices/system/node and report no nodes at all.
+func GetNumaNodes() ([]NumaNodeInfo, error) {
+	topology, err := ghw.Topology()
+	if err != nil {
+		return nil, fmt.Errorf("error getting topology info: %v", err)
+	}
+
+	var nodes []NumaNodeInfo
+	for _, node := range topology.Nodes {
+		info := NumaNodeInfo{
+			ID:        node.ID,
+			Cpus:      []int{},
+			Distances: node.Distances,
+		}
+
+		for _, core := range node.Cores {
+			info.Cpus = append(info.Cpus, core.LogicalProcessors...)
+		}
+		slices.Sort(info.Cpus)
+
+		if node.Memory != nil {
+			info.MemoryBytes = node.Memory.TotalUsableBytes
+		}
+
+		for _, cache := range node.Caches {
+			kind := CacheInfo{
+				Level:     int(cache.Level),
+				Type:      strings.ToLower(cache.Type.String()),
+				SizeBytes: int64(cache.SizeBytes),
+			}
+			i := slices.IndexFunc(info.Caches, func(c CacheInfo) bool {
+				return c.Level == kind.Level && c.Type == kind.Type && c.SizeBytes == kind.SizeBytes
+			})
+			if i < 0 {
+				info.Caches = append(info.Caches, kind)
+				i = len(info.Caches) - 1
+			}
+			info.Caches[i].Count++
+		}
+		slices.SortFunc(info.Caches, func(a, b CacheInfo) int {
+			return cmp.Or(cmp.Compare(a.Level, b.Level), strings.Compare(a.Type, b.Type))
+		})
+
+		nodes = append(nodes, info)
+	}
+
+	return nodes, nil
+}
@@ -0,0 +1,124 @@
+package fingerprint
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/lunarhue/libs-go/log"
+)
+
+// SmartHealth summarizes a disk's SMART or NVMe health log.
+type SmartHealth struct {
+	// Passed is the drive's own overall assessment.
+	Passed       bool  `json:"passed"`
+	TemperatureC int   `json:"temperature_c,omitempty"`
+	PowerOnHours int64 `json:"power_on_hours,omitempty"`
+	// ReallocatedSectors is the ATA reallocated sector count, a common
+	// early sign of a failing spinning disk.
+	ReallocatedSectors int64 `json:"reallocated_sectors,omitempty"`
+	// PercentageUsed is the NVMe estimate of the drive's consumed endurance.
+	// It may exceed 100.
+	PercentageUsed int   `json:"percentage_used,omitempty"`
+	MediaErrors    int64 `json:"media_errors,omitempty"`
+}
+
+// smartctlOutput is the part of `smartctl --json` output SmartHealth is
+// built from.
+type smartctlOutput struct {
+	SmartStatus *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature struct {
+		Current int `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime struct {
+		Hours int64 `json:"hours"`
+	} `json:"power_on_time"`
+	AtaSmartAttributes struct {
+		Table []struct {
+			ID  int `json:"id"`
+			Raw struct {
+				Value int64 `json:"value"`
+			} `json:"raw"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+	NvmeSmartHealthInformationLog struct {
+		PercentageUsed int   `json:"percentage_used"`
+		MediaErrors    int64 `json:"media_errors"`
+	} `json:"nvme_smart_health_information_log"`
+}
+
+const ataReallocatedSectorCount = 5
+
+// smartTimeout bounds probing all disks together. The controller gives the
+// whole fingerprint 30 seconds, which has to cover everything else too.
+const smartTimeout = 15 * time.Second
+
+// getSmartHealths probes disks in parallel under one shared deadline and
+// returns their health in the same order. A disk whose probe doesn't finish
+// in time is left without health rather than holding up the others.
+func getSmartHealths(disks []string) []*SmartHealth {
+	ctx, cancel := context.WithTimeout(context.Background(), smartTimeout)
+	defer cancel()
+
+	healths := make([]*SmartHealth, len(disks))
+	var wg sync.WaitGroup
+	for i, disk := range disks {
+		wg.Go(func() {
+			healths[i] = GetSmartHealth(ctx, disk)
+		})
+	}
+	wg.Wait()
+
+	return healths
+}
+
+// GetSmartHealth asks smartctl for the health of disk. It returns nil if
+// smartctl isn't installed, can't read the disk, which needs root, or the
+// disk doesn't support SMART. Disks in standby are left asleep and have no
+// health either. Hardware read from a fixture has no health.
+func GetSmartHealth(ctx context.Context, disk string) *SmartHealth {
+	if !onLiveHost() {
+		return nil
+	}
+
+	path, err := exec.LookPath("smartctl")
+	if err != nil {
+		return nil
+	}
+
+	// smartctl reports problems with the disk in its exit status, so its
+	// output is worth reading even if it fails.
+	out, err := exec.CommandContext(ctx, path, "--json", "-n", "standby", "--health", "--info", "--attributes", "/dev/"+disk).Output()
+	if len(out) == 0 {
+		log.Debugf("smartctl failed for %s: %v", disk, err)
+		return nil
+	}
+
+	var result smartctlOutput
+	if err := json.Unmarshal(out, &result); err != nil {
+		log.Debugf("Unable to parse smartctl output for %s: %v", disk, err)
+		return nil
+	}
+	if result.SmartStatus == nil {
+		return nil
+	}
+
+	health := &SmartHealth{
+		Passed:         result.SmartStatus.Passed,
+		TemperatureC:   result.Temperature.Current,
+		PowerOnHours:   result.PowerOnTime.Hours,
+		PercentageUsed: result.NvmeSmartHealthInformationLog.PercentageUsed,
+		MediaErrors:    result.NvmeSmartHealthInformationLog.MediaErrors,
+	}
+	for _, attr := range result.AtaSmartAttributes.Table {
+		if attr.ID == ataReallocatedSectorCount {
+			health.ReallocatedSectors = attr.Raw.Value
+		}
+	}
+
+	return health
+}
@@ -2,30 +2,146 @@ package fingerprint
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/jaypipes/ghw"
+	"github.com/jaypipes/ghw/pkg/block"
+)
+
+// Buses a disk can be attached to.
+const (
+	BusNVMe    = "nvme"
+	BusSATA    = "sata"
+	BusSAS     = "sas"
+	BusSCSI    = "scsi"
+	BusUSB     = "usb"
+	BusVirtIO  = "virtio"
+	BusMMC     = "mmc"
+	BusIDE     = "ide"
+	BusUnknown = "unknown"
 )
 
 type StorageInfo struct {
-	DeviceName string `json:"device_name"`
-	Model      string `json:"model"`
+	DeviceName string          `json:"device_name"`
+	Model      string          `json:"model"`
+	SizeBytes  int64           `json:"size_bytes"`
+	Serial     string          `json:"serial,omitempty"`
+	Wwn        string          `json:"wwn,omitempty"`
+	Bus        string          `json:"bus"`
+	Rotational bool            `json:"rotational"`
+	Removable  bool            `json:"removable"`
+	Partitions []PartitionInfo `json:"partitions,omitempty"`
+	// Health is nil if smartctl isn't installed or couldn't read the disk,
+	// which needs root.
+	Health *SmartHealth `json:"health,omitempty"`
+}
+
+type PartitionInfo struct {
+	Name       string `json:"name"`
 	SizeBytes  int64  `json:"size_bytes"`
+	Filesystem string `json:"filesystem,omitempty"`
+	Label      string `json:"label,omitempty"`
+	UUID       string `json:"uuid,omitempty"`
+	MountPoint string `json:"mount_point,omitempty"`
 }
 
+// virtualDisks are name prefixes of block devices that aren't backed by a
+// disk of their own: loop and RAM disks, swap in RAM, device-mapper and
+// software RAID volumes, whose member disks are listed anyway, and network
+// block devices.
+var virtualDisks = []string{"loop", "ram", "zram", "dm-", "md", "nbd"}
+
 func GetStorageDevices() ([]StorageInfo, error) {
-	block, err := ghw.Block()
+	blockInfo, err := ghw.Block()
 	if err != nil {
 		return nil, fmt.Errorf("error getting storage info: %v", err)
 	}
 
 	var devices []StorageInfo
-	for _, disk := range block.Disks {
+	for _, disk := range blockInfo.Disks {
+		if isVirtualDisk(disk) {
+			continue
+		}
+
+		var partitions []PartitionInfo
+		for _, part := range disk.Partitions {
+			partitions = append(partitions, PartitionInfo{
+				Name:       part.Name,
+				SizeBytes:  int64(part.SizeBytes),
+				Filesystem: orEmpty(part.Type),
+				Label:      orEmpty(part.FilesystemLabel),
+				UUID:       orEmpty(part.UUID),
+				MountPoint: part.MountPoint,
+			})
+		}
+
 		devices = append(devices, StorageInfo{
 			DeviceName: disk.Name,
 			Model:      disk.Model,
 			SizeBytes:  int64(disk.SizeBytes),
+			Serial:     orEmpty(disk.SerialNumber),
+			Wwn:        orEmpty(disk.WWN),
+			Bus:        diskBus(disk),
+			Rotational: disk.DriveType == block.DriveTypeHDD,
+			Removable:  disk.IsRemovable,
+			Partitions: partitions,
 		})
 	}
 
+	names := make([]string, len(devices))
+	for i, device := range devices {
+		names[i] = device.DeviceName
+	}
+	for i, health := range getSmartHealths(names) {
+		devices[i].Health = health
+	}
+
 	return devices, nil
 }
+
+func isVirtualDisk(disk *block.Disk) bool {
+	if disk.DriveType == block.DriveTypeVirtual || disk.StorageController == block.StorageControllerLoop {
+		return true
+	}
+	for _, prefix := range virtualDisks {
+		if strings.HasPrefix(disk.Name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// diskBus tells the bus a disk hangs off. ghw lumps SATA, SAS and USB disks
+// together as SCSI; the udev bus path tells them apart.
+func diskBus(disk *block.Disk) string {
+	switch disk.StorageController {
+	case block.StorageControllerNVMe:
+		return BusNVMe
+	case block.StorageControllerVirtIO:
+		return BusVirtIO
+	case block.StorageControllerMMC:
+		return BusMMC
+	case block.StorageControllerIDE:
+		return BusIDE
+	}
+
+	switch {
+	case strings.Contains(disk.BusPath, "-usb-"):
+		return BusUSB
+	case strings.Contains(disk.BusPath, "-ata-"):
+		return BusSATA
+	case strings.Contains(disk.BusPath, "-sas-"):
+		return BusSAS
+	case disk.StorageController == block.StorageControllerSCSI:
+		return BusSCSI
+	}
+	return BusUnknown
+}
+
+// orEmpty drops the "unknown" ghw reports for values it couldn't read.
+func orEmpty(value string) string {
+	if value == "unknown" {
+		return ""
+	}
+	return value
+}
@@ -8,9 +8,10 @@ import (
 )
 
 type SystemInfo struct {
-	Arch       string `json:"arch"`
-	Hostname   string `json:"hostname"`
-	TpmVersion string `json:"tpm_version"`
+	Arch       string  `json:"arch"`
+	Hostname   string  `json:"hostname"`
+	TpmVersion string  `json:"tpm_version"`
+	Dmi        DmiInfo `json:"dmi"`
 }
 
 func GetSystemInfo() (SystemInfo, error) {
@@ -23,6 +24,7 @@ func GetSystemInfo() (SystemInfo, error) {
 		Arch:       runtime.GOARCH,
 		Hostname:   name,
 		TpmVersion: getLinuxTPMVersion(),
+		Dmi:        GetDmiInfo(),
 	}
 
 	return info, nil
@@ -0,0 +1,78 @@
+package inventory
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType says what happened to a node.
+type EventType string
+
+const (
+	EventAdded   EventType = "added"
+	EventUpdated EventType = "updated"
+	EventRemoved EventType = "removed"
+	// EventAppeared and EventDisappeared are updates that changed whether
+	// the node is present in discovery.
+	EventAppeared    EventType = "appeared"
+	EventDisappeared EventType = "disappeared"
+)
+
+// Event is published for every change to the inventory.
+type Event struct {
+	Type EventType
+	// Node is the node after the change, or the last known node once it
+	// was removed.
+	Node *Node
+	// PreviousState is the node's state before the change.
+	PreviousState State
+	Time          time.Time
+}
+
+// subscriberBuffer is how many events a subscriber may fall behind before it
+// is dropped.
+const subscriberBuffer = 256
+
+type subscribers struct {
+	mu    sync.Mutex
+	chans map[chan Event]struct{}
+}
+
+// Subscribe returns a channel receiving every change from now on and a
+// function to stop receiving them. A subscriber that falls too far behind has
+// its channel closed rather than silently missing events.
+func (s *Store) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	s.subs.mu.Lock()
+	if s.subs.chans == nil {
+		s.subs.chans = make(map[chan Event]struct{})
+	}
+	s.subs.chans[ch] = struct{}{}
+	s.subs.mu.Unlock()
+
+	return ch, func() {
+		s.subs.mu.Lock()
+		defer s.subs.mu.Unlock()
+		if _, ok := s.subs.chans[ch]; ok {
+			delete(s.subs.chans, ch)
+			close(ch)
+		}
+	}
+}
+
+func (s *Store) publish(eventType EventType, node *Node, previous State) {
+	s.subs.mu.Lock()
+	defer s.subs.mu.Unlock()
+
+	for ch := range s.subs.chans {
+		// Every subscriber gets its own copy to read without locking.
+		copied := *node
+		select {
+		case ch <- Event{Type: eventType, Node: &copied, PreviousState: previous, Time: time.Now()}:
+		default:
+			delete(s.subs.chans, ch)
+			close(ch)
+		}
+	}
+}
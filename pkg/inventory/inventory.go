@@ -0,0 +1,181 @@
+package inventory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var nodesBucket = []byte("nodes")
+
+// ErrNotFound is returned when a node is not in the inventory.
+var ErrNotFound = errors.New("node not found")
+
+// Store is the controller's durable record of every node it has seen.
+// It is backed by a single bbolt file and safe for concurrent use.
+type Store struct {
+	db   *bolt.DB
+	subs subscribers
+}
+
+// Open opens (or creates) the inventory database at path.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create inventory directory: %w", err)
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open inventory %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(nodesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize inventory: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Get returns the node with the given ID or ErrNotFound.
+func (s *Store) Get(id string) (*Node, error) {
+	var node *Node
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		node, err = getNode(tx.Bucket(nodesBucket), id)
+		return err
+	})
+	return node, err
+}
+
+// List returns every node in the inventory ordered by ID.
+func (s *Store) List() ([]*Node, error) {
+	var nodes []*Node
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(nodesBucket).ForEach(func(k, v []byte) error {
+			var node Node
+			if err := json.Unmarshal(v, &node); err != nil {
+				return fmt.Errorf("failed to decode node %s: %w", k, err)
+			}
+			nodes = append(nodes, &node)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes, nil
+}
+
+// Update loads the node with the given ID, creating it if it doesn't exist,
+// lets fn modify it and writes it back in a single transaction. If fn returns
+// an error nothing is written.
+func (s *Store) Update(id string, fn func(node *Node) error) (*Node, error) {
+	return s.update(id, true, fn)
+}
+
+// UpdateExisting is like Update but returns ErrNotFound instead of creating
+// the node.
+func (s *Store) UpdateExisting(id string, fn func(node *Node) error) (*Node, error) {
+	return s.update(id, false, fn)
+}
+
+func (s *Store) update(id string, create bool, fn func(node *Node) error) (*Node, error) {
+	var node *Node
+	var previous State
+	var wasPresent bool
+	created := false
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(nodesBucket)
+
+		var err error
+		node, err = getNode(bucket, id)
+		if errors.Is(err, ErrNotFound) && create {
+			node = &Node{ID: id, FirstSeen: time.Now()}
+			created = true
+		} else if err != nil {
+			return err
+		}
+		previous = node.State
+		wasPresent = node.Present
+
+		if err := fn(node); err != nil {
+			return err
+		}
+		node.UpdatedAt = time.Now()
+
+		return putNode(bucket, node)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case created:
+		s.publish(EventAdded, node, previous)
+	case node.Present && !wasPresent:
+		s.publish(EventAppeared, node, previous)
+	case !node.Present && wasPresent:
+		s.publish(EventDisappeared, node, previous)
+	default:
+		s.publish(EventUpdated, node, previous)
+	}
+	return node, nil
+}
+
+// Delete removes the node with the given ID.
+func (s *Store) Delete(id string) error {
+	var node *Node
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(nodesBucket)
+
+		var err error
+		if node, err = getNode(bucket, id); err != nil {
+			return err
+		}
+		return bucket.Delete([]byte(id))
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publish(EventRemoved, node, node.State)
+	return nil
+}
+
+func getNode(bucket *bolt.Bucket, id string) (*Node, error) {
+	data := bucket.Get([]byte(id))
+	if data == nil {
+		return nil, ErrNotFound
+	}
+
+	var node Node
+	if err := json.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("failed to decode node %s: %w", id, err)
+	}
+	return &node, nil
+}
+
+func putNode(bucket *bolt.Bucket, node *Node) error {
+	data, err := json.Marshal(node)
+	if err != nil {
+		return fmt.Errorf("failed to encode node %s: %w", node.ID, err)
+	}
+	return bucket.Put([]byte(node.ID), data)
+}
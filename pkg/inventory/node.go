@@ -0,0 +1,131 @@
+package inventory
+
+import (
+	"time"
+
+	"github.com/lunarhue/metallic-flock/pkg/fingerprint"
+)
+
+// State is where a node is in its lifecycle as seen by the controller.
+type State string
+
+const (
+	// StateDiscovered means the node was seen advertising itself as pending.
+	StateDiscovered State = "discovered"
+	// StateAwaitingApproval means the adoption policy requires an operator
+	// to approve the node before it is adopted.
+	StateAwaitingApproval State = "awaiting_approval"
+	// StateRejected means an operator rejected the node. It is never adopted.
+	StateRejected State = "rejected"
+	// StateAdopting means an adoption attempt is in flight.
+	StateAdopting State = "adopting"
+	// StateAdopted means the node accepted the adoption command.
+	StateAdopted State = "adopted"
+	// StateFailed means the last adoption attempt failed.
+	StateFailed State = "failed"
+	// StateLost means an adopted node stopped checking in.
+	StateLost State = "lost"
+	// StateResetting means an operator asked the node to revert to pending.
+	// The request is delivered with the node's next heartbeat.
+	StateResetting State = "resetting"
+)
+
+// Node is everything the controller remembers about a single node.
+type Node struct {
+	// ID is the name the node advertises itself under in discovery.
+	ID       string `json:"id"`
+	Hostname string `json:"hostname,omitempty"`
+	IP       string `json:"ip,omitempty"`
+	Port     uint16 `json:"port,omitempty"`
+	Role     string `json:"role,omitempty"`
+	State    State  `json:"state"`
+
+	// Approved is set once an operator approved the node, so it can be
+	// retried without asking again.
+	Approved bool `json:"approved,omitempty"`
+
+	// Metadata is the hardware summary the node advertised in its
+	// discovery TXT records.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Fingerprint is the full hardware inventory reported by the node
+	// itself. It is nil until the controller managed to fetch it.
+	Fingerprint *fingerprint.Fingerprint `json:"fingerprint,omitempty"`
+
+	// Present is set while the node advertises itself in discovery, as a
+	// pending node or as a member of the cluster.
+	Present bool `json:"present,omitempty"`
+
+	// Status is the most recent health report from the node's heartbeat.
+	Status *Status `json:"status,omitempty"`
+
+	// AdoptionPhase is the last progress the node reported while joining,
+	// e.g. unit_spawned or kubelet_registered.
+	AdoptionPhase string `json:"adoption_phase,omitempty"`
+
+	// LastError describes why the last adoption attempt failed, if it did.
+	LastError string `json:"last_error,omitempty"`
+
+	// Attempts are the most recent adoption attempts, oldest first.
+	Attempts []Attempt `json:"attempts,omitempty"`
+	// NextAttemptAt is when a failed adoption will be retried.
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+
+	FirstSeen         time.Time  `json:"first_seen"`
+	LastSeen          time.Time  `json:"last_seen"`
+	AdoptionStartedAt *time.Time `json:"adoption_started_at,omitempty"`
+	AdoptedAt         *time.Time `json:"adopted_at,omitempty"`
+	LastHeartbeat     *time.Time `json:"last_heartbeat,omitempty"`
+	StateChangedAt    time.Time  `json:"state_changed_at"`
+	PresenceChangedAt *time.Time `json:"presence_changed_at,omitempty"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// maxAttempts is how many adoption attempts are kept per node.
+const maxAttempts = 10
+
+// Attempt is the outcome of a single adoption attempt.
+type Attempt struct {
+	Role       string    `json:"role"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// RecordAttempt appends attempt to the node's history, dropping the oldest
+// attempts once there are too many.
+func (n *Node) RecordAttempt(attempt Attempt) {
+	n.Attempts = append(n.Attempts, attempt)
+	if len(n.Attempts) > maxAttempts {
+		n.Attempts = n.Attempts[len(n.Attempts)-maxAttempts:]
+	}
+}
+
+// Status is a node's self-reported health.
+type Status struct {
+	K3sUnit              string  `json:"k3s_unit"`
+	Load1                float64 `json:"load1"`
+	Load5                float64 `json:"load5"`
+	Load15               float64 `json:"load15"`
+	MemoryTotalBytes     uint64  `json:"memory_total_bytes"`
+	MemoryAvailableBytes uint64  `json:"memory_available_bytes"`
+}
+
+// SetPresent records whether the node is advertising itself.
+func (n *Node) SetPresent(present bool) {
+	if n.Present == present {
+		return
+	}
+	now := time.Now()
+	n.Present = present
+	n.PresenceChangedAt = &now
+}
+
+// SetState moves the node to state, recording when it happened.
+func (n *Node) SetState(state State) {
+	if n.State == state {
+		return
+	}
+	n.State = state
+	n.StateChangedAt = time.Now()
+}
@@ -4,55 +4,123 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/lunarhue/libs-go/log"
 )
 
-func StartAgent(serverURL string, token string) error {
-	const unitName = "k3s-agent.service"
+// Roles a node can be adopted as.
+const (
+	RoleAgent  = "agent"
+	RoleServer = "server"
+)
+
+// Transient systemd units the k3s agent and joined k3s servers run in.
+const (
+	AgentUnitName  = "k3s-agent.service"
+	ServerUnitName = "k3s-server.service"
+)
+
+// UnitName returns the transient unit a node adopted as role runs k3s in.
+func UnitName(role string) string {
+	if role == RoleServer {
+		return ServerUnitName
+	}
+	return AgentUnitName
+}
+
+// StartAgent joins this node to the cluster at serverURL as an agent. A
+// non-empty nodeName replaces the hostname as the Kubernetes node name.
+// nodeIPs, one per address family for dual-stack, are the addresses the node
+// registers with; k3s picks one itself if there are none.
+func StartAgent(serverURL string, token string, nodeName string, nodeIPs []string) error {
+	return startTransientUnit(AgentUnitName, "K3s Agent", serverURL, token, nodeName, nodeIPs,
+		"agent",
+		"--server", serverURL,
+	)
+}
 
+// StartServer joins this node to the cluster at serverURL as an additional
+// server. The existing server must run embedded etcd (--cluster-init) and
+// token must be its server token, not a bootstrap token.
+func StartServer(serverURL string, token string, nodeName string, nodeIPs []string) error {
+	return startTransientUnit(ServerUnitName, "K3s Server", serverURL, token, nodeName, nodeIPs,
+		"server",
+		"--server", serverURL,
+	)
+}
+
+func startTransientUnit(unitName, description, serverURL, token, nodeName string, nodeIPs []string, args ...string) error {
 	binPath, err := exec.LookPath("k3s")
 	if err != nil {
 		return fmt.Errorf("k3s binary not found in PATH: %w", err)
 	}
 
+	// Pass the token through a root-only file. On the command line it would
+	// show up in ps and in the unit's properties.
+	tokenFile, err := writeTokenFile(unitName, token)
+	if err != nil {
+		return err
+	}
+	args = append(args, "--token-file", tokenFile)
+
+	if nodeName != "" {
+		args = append(args, "--node-name", nodeName)
+	}
+	if len(nodeIPs) > 0 {
+		args = append(args, "--node-ip", strings.Join(nodeIPs, ","))
+	}
+
 	log.Infof("Ensuring previous %s is stopped...", unitName)
 	_ = exec.Command("systemctl", "stop", unitName).Run()
 
 	// 3. Construct the systemd-run command
-	// This creates a service named 'k3s-agent' that restarts automatically if it fails.
+	// This creates a service that restarts automatically if it fails.
 	// equivalent to: systemd-run --unit=k3s-agent -p Restart=always k3s agent ...
-	cmd := exec.Command("systemd-run",
-		"--unit="+unitName,
-		"--description=K3s Agent (Transient)",
+	cmd := exec.Command("systemd-run", append([]string{
+		"--unit=" + unitName,
+		"--description=" + description + " (Transient)",
 		"-p", "Restart=always", // Auto-restart if it crashes
 		"-p", "RestartSec=10", // Wait 10s before restarting
 		binPath, // Command to run
-		"agent",
-		"--server", serverURL,
-		"--token", token,
-	)
+	}, args...)...)
 
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
-	log.Infof("Spawning K3s Agent via systemd-run against %s...", serverURL)
+	log.Infof("Spawning %s via systemd-run against %s...", description, serverURL)
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to spawn k3s-agent service: %w", err)
+		StopUnit(unitName)
+		return fmt.Errorf("failed to spawn %s: %w", unitName, err)
 	}
 
 	// 4. Verification (Optional but recommended)
 	// Give systemd a moment to spin it up and check status
 	time.Sleep(1 * time.Second)
 	if err := checkServiceRunning(unitName); err != nil {
-		return fmt.Errorf("agent service failed to start: %w", err)
+		StopUnit(unitName)
+		return fmt.Errorf("%s failed to start: %w", unitName, err)
 	}
 
-	log.Infof("SUCCESS: K3s Agent is running in background unit '%s'", unitName)
+	log.Infof("SUCCESS: %s is running in background unit '%s'", description, unitName)
 	return nil
 }
 
+// StopUnit stops a transient unit started by StartAgent or StartServer that
+// failed to join, and removes its join token. Unlike Stop it keeps the state
+// k3s wrote, so the next attempt can reuse it.
+func StopUnit(unitName string) {
+	log.Infof("Stopping %s...", unitName)
+	_ = exec.Command("systemctl", "stop", unitName).Run()
+	// Transient units that failed stick around until their failure is cleared.
+	_ = exec.Command("systemctl", "reset-failed", unitName).Run()
+
+	if err := os.Remove(tokenFile(unitName)); err != nil && !os.IsNotExist(err) {
+		log.Warnf("Failed to remove join token of %s: %v", unitName, err)
+	}
+}
+
 func checkServiceRunning(unitName string) error {
 	cmd := exec.Command("systemctl", "is-active", unitName)
 	output, _ := cmd.Output()
@@ -69,3 +137,83 @@ func checkServiceRunning(unitName string) error {
 	logs, _ := exec.Command("journalctl", "-u", unitName, "-n", "10", "--no-pager").CombinedOutput()
 	return fmt.Errorf("service state is '%s'. Recent logs:\n%s", state, string(logs))
 }
+
+// UnitStatus returns the systemd ActiveState of unitName, e.g. "active",
+// "failed" or "inactive".
+func UnitStatus(unitName string) string {
+	output, _ := exec.Command("systemctl", "is-active", unitName).Output()
+	state := strings.TrimSpace(string(output))
+	if state == "" {
+		return "unknown"
+	}
+	return state
+}
+
+// kubeletKubeconfig is the kubeconfig k3s writes for the kubelet of an agent
+// or joined server. Its credentials may read the node's own Node object.
+const kubeletKubeconfig = "/var/lib/rancher/k3s/agent/kubelet.kubeconfig"
+
+// NodeRegistration reports whether this node's kubelet registered nodeName
+// with the cluster and whether the node is Ready.
+func NodeRegistration(nodeName string) (registered bool, ready bool, err error) {
+	if _, err := os.Stat(kubeletKubeconfig); err != nil {
+		// k3s writes it once it has joined, before the kubelet starts.
+		return false, false, nil
+	}
+
+	cmd := exec.Command("k3s", "kubectl", "--kubeconfig", kubeletKubeconfig,
+		"get", "node", nodeName, "--ignore-not-found",
+		"-o", `jsonpath={.metadata.name} {.status.conditions[?(@.type=="Ready")].status}`)
+	output, err := cmd.Output()
+	if err != nil {
+		return false, false, fmt.Errorf("failed to query node %s: %w", nodeName, err)
+	}
+
+	name, readyStatus, _ := strings.Cut(strings.TrimSpace(string(output)), " ")
+	if name == "" {
+		return false, false, nil
+	}
+
+	return true, readyStatus == "True", nil
+}
+
+// nodeStateDirs hold what a k3s agent or joined server keeps between runs:
+// certificates, containerd state, the embedded etcd member, the node
+// password the server checks on rejoin and the join token.
+var nodeStateDirs = []string{
+	"/var/lib/rancher/k3s/agent",
+	"/var/lib/rancher/k3s/server",
+	"/etc/rancher/node",
+	tokenDir,
+}
+
+// Stop stops the transient unit started by StartAgent or StartServer and
+// removes the state k3s keeps for it, so the node can join a cluster from
+// scratch. It must never run on the controller, whose k3s.service shares the
+// server state directory.
+func Stop() error {
+	for _, unitName := range []string{AgentUnitName, ServerUnitName} {
+		StopUnit(unitName)
+
+		if state := UnitStatus(unitName); state == "active" || state == "activating" {
+			return fmt.Errorf("%s is still %s after stopping it", unitName, state)
+		}
+	}
+
+	// Stopping the unit leaves the pods' containers running. The killall
+	// script shipped with k3s cleans those up along with their mounts.
+	if killall, err := exec.LookPath("k3s-killall.sh"); err == nil {
+		if output, err := exec.Command(killall).CombinedOutput(); err != nil {
+			log.Warnf("k3s-killall.sh failed: %v\n%s", err, output)
+		}
+	}
+
+	for _, dir := range nodeStateDirs {
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", dir, err)
+		}
+	}
+
+	log.Infof("K3s stopped and its state wiped.")
+	return nil
+}
@@ -3,7 +3,9 @@ package k3s
 import (
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
+	"strings"
 	"time"
 )
 
@@ -60,3 +62,25 @@ func waitForServiceState(ctx context.Context, serviceName, targetState string) e
 		}
 	}
 }
+
+// DeleteNode removes a node object (and with it the node password secret) from
+// the cluster so the machine can rejoin later under the same name.
+func DeleteNode(name string) error {
+	binPath, err := exec.LookPath("k3s")
+	if err != nil {
+		return fmt.Errorf("k3s binary not found in PATH: %w", err)
+	}
+
+	cmd := exec.Command(binPath, "kubectl", "delete", "node", name, "--ignore-not-found", "--wait=false")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to delete node %s: %s: %w", name, strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// UsesEmbeddedEtcd reports whether the local k3s server runs embedded etcd,
+// i.e. was started with --cluster-init. Only then can other servers join it.
+func UsesEmbeddedEtcd() bool {
+	info, err := os.Stat("/var/lib/rancher/k3s/server/db/etcd")
+	return err == nil && info.IsDir()
+}
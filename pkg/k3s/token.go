@@ -3,7 +3,9 @@ package k3s
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 )
@@ -48,3 +50,69 @@ func CreateJoinToken(description string, ttl time.Duration) (string, error) {
 
 	return token, nil
 }
+
+// tokenDir holds the join tokens handed to the transient k3s units.
+const tokenDir = "/etc/rancher/metallic-flock"
+
+// tokenFile is where the join token of unitName is kept.
+func tokenFile(unitName string) string {
+	return filepath.Join(tokenDir, strings.TrimSuffix(unitName, ".service")+".token")
+}
+
+// writeTokenFile stores token where only root can read it and returns the
+// path to pass to k3s as --token-file.
+func writeTokenFile(unitName, token string) (string, error) {
+	if err := os.MkdirAll(tokenDir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create token directory: %w", err)
+	}
+
+	path := tokenFile(unitName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(token+"\n"), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write token file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to write token file: %w", err)
+	}
+
+	return path, nil
+}
+
+// RedactToken returns a form of token that is safe to log. Bootstrap tokens
+// keep their public ID so they can be matched against 'k3s token list'.
+func RedactToken(token string) string {
+	if token == "" {
+		return ""
+	}
+
+	// Secure tokens carry the CA hash and credentials after "::".
+	if _, creds, ok := strings.Cut(token, "::"); ok {
+		token = creds
+	}
+
+	if id, _, ok := strings.Cut(token, "."); ok && len(id) == 6 {
+		return id + ".<redacted>"
+	}
+	return "<redacted>"
+}
+
+// serverTokenPath holds the server token in its secure form, which includes
+// the hash of the cluster CA.
+const serverTokenPath = "/var/lib/rancher/k3s/server/token"
+
+// ReadServerToken returns the token additional servers need to join this
+// one. Servers can't join with the bootstrap tokens from CreateJoinToken.
+func ReadServerToken() (string, error) {
+	data, err := os.ReadFile(serverTokenPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read server token: %w", err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("server token at %s is empty", serverTokenPath)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,269 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"slices"
+)
+
+// Address families a Selector can prefer.
+const (
+	FamilyIPv4 = "ipv4"
+	FamilyIPv6 = "ipv6"
+)
+
+// Selector decides which local addresses the node advertises, binds to and
+// hands to peers, and which of a peer's addresses it talks to. Without
+// interfaces or CIDRs every interface qualifies. A nil Selector behaves like
+// an empty one.
+type Selector struct {
+	// Interfaces restricts the node to these network interfaces.
+	Interfaces []string
+	// CIDRs restricts the node to addresses within these networks, and
+	// peers' addresses within them are preferred.
+	CIDRs []*net.IPNet
+	// PreferIPv6 prefers IPv6 over IPv4 addresses of the same kind.
+	PreferIPv6 bool
+	// DualStack registers nodes with k3s under an address of each family.
+	DualStack bool
+}
+
+// New parses the network config into a Selector.
+func New(interfaces, cidrs []string, family string) (*Selector, error) {
+	s := &Selector{Interfaces: interfaces}
+
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+		}
+		s.CIDRs = append(s.CIDRs, ipNet)
+	}
+
+	switch family {
+	case FamilyIPv4, "":
+	case FamilyIPv6:
+		s.PreferIPv6 = true
+	default:
+		return nil, fmt.Errorf("unknown address family %q (expected %s or %s)", family, FamilyIPv4, FamilyIPv6)
+	}
+
+	return s, nil
+}
+
+// Restricted reports whether the node is limited to some interfaces or
+// networks.
+func (s *Selector) Restricted() bool {
+	return s != nil && (len(s.Interfaces) > 0 || len(s.CIDRs) > 0)
+}
+
+// NetInterfaces returns the interfaces the node may use. It has the signature
+// zeroconf expects for its interface list.
+func (s *Selector) NetInterfaces() ([]net.Interface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	if s == nil || len(s.Interfaces) == 0 {
+		return ifaces, nil
+	}
+
+	var selected []net.Interface
+	for _, ifi := range ifaces {
+		if slices.Contains(s.Interfaces, ifi.Name) {
+			selected = append(selected, ifi)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("none of the interfaces %v exist", s.Interfaces)
+	}
+	return selected, nil
+}
+
+// InterfaceAddrs returns the addresses of ifi the node may use. It has the
+// signature zeroconf expects for looking up interface addresses.
+func (s *Selector) InterfaceAddrs(ifi *net.Interface) ([]net.Addr, error) {
+	addrs, err := ifi.Addrs()
+	if err != nil {
+		return nil, err
+	}
+	if s == nil || len(s.CIDRs) == 0 {
+		return addrs, nil
+	}
+
+	var selected []net.Addr
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && s.inCIDRs(ipNet.IP) {
+			selected = append(selected, addr)
+		}
+	}
+	return selected, nil
+}
+
+// LocalAddrs returns the node's usable addresses with the network they are
+// on, best first, IPv4 and IPv6 alike. Loopback interfaces are left out
+// unless named explicitly.
+func (s *Selector) LocalAddrs() ([]*net.IPNet, error) {
+	ifaces, err := s.NetInterfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var local []*net.IPNet
+	for _, ifi := range ifaces {
+		if ifi.Flags&net.FlagUp == 0 {
+			continue
+		}
+		if ifi.Flags&net.FlagLoopback != 0 && (s == nil || !slices.Contains(s.Interfaces, ifi.Name)) {
+			continue
+		}
+
+		addrs, err := s.InterfaceAddrs(&ifi)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list addresses of %s: %w", ifi.Name, err)
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok {
+				local = append(local, ipNet)
+			}
+		}
+	}
+
+	slices.SortStableFunc(local, func(a, b *net.IPNet) int {
+		return s.rank(a.IP) - s.rank(b.IP)
+	})
+	return local, nil
+}
+
+// ListenHost returns the address to serve on: empty for every address if the
+// node isn't restricted, otherwise the best selected one.
+func (s *Selector) ListenHost() (string, error) {
+	if !s.Restricted() {
+		return "", nil
+	}
+
+	local, err := s.LocalAddrs()
+	if err != nil {
+		return "", err
+	}
+	if len(local) == 0 {
+		return "", fmt.Errorf("no address matches the network config")
+	}
+	return local[0].IP.String(), nil
+}
+
+// SourceIP returns the local address peer should use to reach this node. The
+// kernel's choice for the route to peer is used if the node may use it.
+// Otherwise a selected address on the peer's network wins over the best
+// selected address overall. Nothing is sent to peer.
+func (s *Selector) SourceIP(peer string) (string, error) {
+	peerIP := net.ParseIP(peer)
+	if peerIP == nil {
+		return "", fmt.Errorf("invalid peer address %q", peer)
+	}
+
+	local, err := s.LocalAddrs()
+	if err != nil {
+		return "", err
+	}
+
+	// Connecting a UDP socket only looks up the route.
+	if conn, err := net.Dial("udp", net.JoinHostPort(peer, "9")); err == nil {
+		routed := conn.LocalAddr().(*net.UDPAddr).IP
+		conn.Close()
+		if !s.Restricted() || slices.ContainsFunc(local, func(n *net.IPNet) bool { return n.IP.Equal(routed) }) {
+			return routed.String(), nil
+		}
+	}
+
+	for _, n := range local {
+		if n.Contains(peerIP) {
+			return n.IP.String(), nil
+		}
+	}
+	if len(local) == 0 {
+		return "", fmt.Errorf("no local address to reach %s from", peer)
+	}
+	return local[0].IP.String(), nil
+}
+
+// NodeIPs returns the addresses the node registers with k3s, best first: one
+// per family for dual-stack, otherwise a single one if the node is restricted
+// to some interfaces or networks. It returns none if k3s may choose itself.
+// Link-local addresses are never used.
+func (s *Selector) NodeIPs() ([]string, error) {
+	if !s.Restricted() && (s == nil || !s.DualStack) {
+		return nil, nil
+	}
+
+	local, err := s.LocalAddrs()
+	if err != nil {
+		return nil, err
+	}
+
+	var ips []string
+	var haveIPv4, haveIPv6 bool
+	for _, n := range local {
+		if n.IP.IsLinkLocalUnicast() || n.IP.IsLoopback() {
+			continue
+		}
+		isIPv6 := n.IP.To4() == nil
+		if (isIPv6 && haveIPv6) || (!isIPv6 && haveIPv4) {
+			continue
+		}
+		ips = append(ips, n.IP.String())
+		haveIPv4, haveIPv6 = haveIPv4 || !isIPv6, haveIPv6 || isIPv6
+
+		if !s.DualStack {
+			break
+		}
+	}
+
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no routable address matches the network config")
+	}
+	return ips, nil
+}
+
+// Prefer returns the best of the addresses a peer reported, or nil if there
+// are none.
+func (s *Selector) Prefer(addrs []net.IP) net.IP {
+	if len(addrs) == 0 {
+		return nil
+	}
+	return slices.MinFunc(addrs, func(a, b net.IP) int {
+		return s.rank(a) - s.rank(b)
+	})
+}
+
+// rank orders addresses from most to least useful: addresses in the
+// configured CIDRs, then routable addresses, then link-local and loopback
+// ones, which don't work across hosts without a zone or at all. The preferred
+// family comes first within each group.
+func (s *Selector) rank(ip net.IP) int {
+	var r int
+	switch {
+	case ip.IsLoopback():
+		r = 60
+	case ip.IsLinkLocalUnicast():
+		r = 40
+	case s.inCIDRs(ip):
+		r = 0
+	default:
+		r = 20
+	}
+
+	isIPv6 := ip.To4() == nil
+	preferIPv6 := s != nil && s.PreferIPv6
+	if isIPv6 != preferIPv6 {
+		r += 10
+	}
+	return r
+}
+
+func (s *Selector) inCIDRs(ip net.IP) bool {
+	if s == nil {
+		return false
+	}
+	return slices.ContainsFunc(s.CIDRs, func(n *net.IPNet) bool { return n.Contains(ip) })
+}
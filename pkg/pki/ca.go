@@ -0,0 +1,186 @@
+package pki
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	caCertFile = "ca.crt"
+	caKeyFile  = "ca.key"
+
+	caValidity   = 10 * 365 * 24 * time.Hour
+	nodeValidity = 365 * 24 * time.Hour
+)
+
+// Organizations stamped into issued certificates. They are used to tell
+// controller certificates apart from node certificates signed by the same CA.
+const (
+	OrgController = "metallic-flock:controller"
+	OrgNode       = "metallic-flock:node"
+)
+
+// ControllerServerName is the DNS SAN every controller certificate carries.
+// Nodes verify the controller against it instead of against an IP address,
+// which may change between restarts.
+const ControllerServerName = "metallic-flock-controller"
+
+// CA is the cluster certificate authority owned by the controller.
+type CA struct {
+	Cert    *x509.Certificate
+	CertPEM []byte
+	key     crypto.Signer
+}
+
+// HasCA reports whether dir holds a CA private key, i.e. this machine has run
+// as a controller before.
+func HasCA(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, caKeyFile))
+	return err == nil
+}
+
+// LoadOrCreateCA loads the CA from dir, generating and persisting a new one
+// on first use.
+func LoadOrCreateCA(dir string) (*CA, error) {
+	certPath := filepath.Join(dir, caCertFile)
+	keyPath := filepath.Join(dir, caKeyFile)
+
+	certPEM, certErr := os.ReadFile(certPath)
+	keyPEM, keyErr := os.ReadFile(keyPath)
+
+	switch {
+	case certErr == nil && keyErr == nil:
+		cert, err := parseCertificatePEM(certPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", certPath, err)
+		}
+		key, err := parsePrivateKeyPEM(keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", keyPath, err)
+		}
+		return &CA{Cert: cert, CertPEM: certPEM, key: key}, nil
+	case os.IsNotExist(certErr) && os.IsNotExist(keyErr):
+		return createCA(dir)
+	case certErr == nil:
+		// Silently replacing the CA would invalidate every node certificate
+		// issued so far, so refuse to continue instead.
+		return nil, fmt.Errorf("found %s but failed to read %s: %w", certPath, keyPath, keyErr)
+	default:
+		return nil, fmt.Errorf("failed to read %s: %w", certPath, certErr)
+	}
+}
+
+func createCA(dir string) (*CA, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create pki directory: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %w", err)
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   "metallic-flock-ca",
+			Organization: []string{OrgController},
+		},
+		NotBefore:             now.Add(-5 * time.Minute),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to self-sign CA: %w", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse generated CA: %w", err)
+	}
+
+	keyPEM, err := encodePrivateKeyPEM(key)
+	if err != nil {
+		return nil, err
+	}
+	certPEM := encodeCertificatePEM(der)
+
+	if err := writeFileAtomic(filepath.Join(dir, caKeyFile), keyPEM, 0o600); err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(filepath.Join(dir, caCertFile), certPEM, 0o644); err != nil {
+		return nil, err
+	}
+
+	return &CA{Cert: cert, CertPEM: certPEM, key: key}, nil
+}
+
+// IssueNodeCertificate signs a certificate for a node's public key. The
+// certificate is valid for both serving and dialing so the same key pair can
+// be used in either direction.
+func (ca *CA) IssueNodeCertificate(nodeID string, pub crypto.PublicKey, ips []net.IP) ([]byte, error) {
+	return ca.issue(nodeID, OrgNode, pub, []string{nodeID}, ips)
+}
+
+// IssueControllerCertificate signs a certificate for a controller's public key.
+func (ca *CA) IssueControllerCertificate(nodeID string, pub crypto.PublicKey, ips []net.IP) ([]byte, error) {
+	return ca.issue(nodeID, OrgController, pub, []string{ControllerServerName, nodeID}, ips)
+}
+
+func (ca *CA) issue(commonName, org string, pub crypto.PublicKey, dnsNames []string, ips []net.IP) ([]byte, error) {
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   commonName,
+			Organization: []string{org},
+		},
+		DNSNames:    dnsNames,
+		IPAddresses: ips,
+		NotBefore:   now.Add(-5 * time.Minute),
+		NotAfter:    now.Add(nodeValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, pub, ca.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign certificate for %s: %w", commonName, err)
+	}
+
+	return encodeCertificatePEM(der), nil
+}
+
+func randomSerial() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	return serial, nil
+}
@@ -0,0 +1,40 @@
+package pki
+
+import (
+	"fmt"
+	"slices"
+)
+
+// LoadController loads (or creates) the cluster CA in dir and makes sure the
+// controller's own identity carries a controller certificate issued by it.
+func LoadController(dir, name string) (*CA, *Identity, error) {
+	ca, err := LoadOrCreateCA(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load CA: %w", err)
+	}
+
+	id, err := LoadIdentity(dir, name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load controller identity: %w", err)
+	}
+
+	if id.isController() {
+		return ca, id, nil
+	}
+
+	certPEM, err := ca.IssueControllerCertificate(name, id.PublicKey(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := id.Install(ca.CertPEM, certPEM); err != nil {
+		return nil, nil, fmt.Errorf("failed to install controller certificate: %w", err)
+	}
+
+	return ca, id, nil
+}
+
+func (id *Identity) isController() bool {
+	id.mu.RLock()
+	defer id.mu.RUnlock()
+	return id.issued != nil && slices.Contains(id.issued.Leaf.Subject.Organization, OrgController)
+}
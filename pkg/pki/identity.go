@@ -0,0 +1,393 @@
+package pki
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	nodeKeyFile  = "node.key"
+	nodeCertFile = "node.crt"
+)
+
+// Identity is this node's key pair together with the certificate a controller
+// issued for it. Until a certificate is installed the node presents a
+// self-signed certificate so the controller can learn its public key.
+//
+// The TLS configs handed out by Identity resolve certificates on every
+// handshake, so a server started while pending switches to mutual TLS as soon
+// as the node is adopted, without a restart.
+type Identity struct {
+	dir  string
+	name string
+	key  crypto.Signer
+
+	mu         sync.RWMutex
+	selfSigned tls.Certificate
+	issued     *tls.Certificate
+	caPEM      []byte
+	caPool     *x509.CertPool
+}
+
+// LoadIdentity loads the node key pair from dir, generating one on first use,
+// along with any CA and node certificate persisted by a previous adoption.
+//
+// A ca.crt placed in dir ahead of time pins the node to that CA: adoption
+// requests from controllers holding a different CA are then refused.
+func LoadIdentity(dir, name string) (*Identity, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create pki directory: %w", err)
+	}
+
+	key, err := loadOrCreateKey(filepath.Join(dir, nodeKeyFile))
+	if err != nil {
+		return nil, err
+	}
+
+	id := &Identity{dir: dir, name: name, key: key}
+
+	id.selfSigned, err = selfSignedCertificate(name, key)
+	if err != nil {
+		return nil, err
+	}
+
+	caPEM, err := os.ReadFile(filepath.Join(dir, caCertFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+	if err == nil {
+		if err := id.setCA(caPEM); err != nil {
+			return nil, err
+		}
+	}
+
+	certPEM, err := os.ReadFile(filepath.Join(dir, nodeCertFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read node certificate: %w", err)
+	}
+	if err == nil && id.caPool != nil {
+		cert, err := id.verifyIssued(certPEM, id.caPool)
+		if err != nil {
+			return nil, fmt.Errorf("persisted node certificate is invalid: %w", err)
+		}
+		id.issued = cert
+	}
+
+	return id, nil
+}
+
+// Name returns the name the identity was loaded with.
+func (id *Identity) Name() string {
+	return id.name
+}
+
+// PublicKey returns the node's public key.
+func (id *Identity) PublicKey() crypto.PublicKey {
+	return id.key.Public()
+}
+
+// Adopted reports whether a controller has installed a certificate for this
+// node.
+func (id *Identity) Adopted() bool {
+	id.mu.RLock()
+	defer id.mu.RUnlock()
+	return id.issued != nil
+}
+
+// CAPool returns the pool of trusted CAs, or nil when the node has not been
+// pinned to or adopted by a controller yet.
+func (id *Identity) CAPool() *x509.CertPool {
+	id.mu.RLock()
+	defer id.mu.RUnlock()
+	return id.caPool
+}
+
+// Install validates and persists the CA and node certificate delivered by a
+// controller during adoption.
+func (id *Identity) Install(caPEM, certPEM []byte) error {
+	id.mu.Lock()
+	defer id.mu.Unlock()
+
+	if id.caPEM != nil && !bytes.Equal(id.caPEM, caPEM) {
+		return fmt.Errorf("node is pinned to a different CA")
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("no CA certificate found in adoption request")
+	}
+
+	cert, err := id.verifyIssued(certPEM, pool)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(filepath.Join(id.dir, caCertFile), caPEM, 0o644); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(id.dir, nodeCertFile), certPEM, 0o644); err != nil {
+		return err
+	}
+
+	id.caPEM = caPEM
+	id.caPool = pool
+	id.issued = cert
+	return nil
+}
+
+// Reset forgets the CA and node certificate, including a CA pinned ahead of
+// time, and goes back to presenting the self-signed certificate. The node key
+// is kept.
+func (id *Identity) Reset() error {
+	id.mu.Lock()
+	defer id.mu.Unlock()
+
+	for _, name := range []string{nodeCertFile, caCertFile} {
+		if err := os.Remove(filepath.Join(id.dir, name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+	}
+
+	id.caPEM = nil
+	id.caPool = nil
+	id.issued = nil
+	return nil
+}
+
+// ServerTLSConfig returns the TLS config for the node-facing gRPC server.
+// Clients must present a certificate issued to a holder of one of the given
+// organizations by the trusted CA. While the node is neither adopted nor
+// pinned any client certificate is accepted; the adoption handler then checks
+// that the caller holds the CA it is handing over.
+func (id *Identity) ServerTLSConfig(orgs ...string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS13,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert := id.currentCertificate()
+			pool := id.CAPool()
+
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS13,
+				Certificates: []tls.Certificate{cert},
+				ClientAuth:   tls.RequireAnyClientCert,
+			}
+			if pool != nil {
+				cfg.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+					return VerifyPeer(rawCerts, pool, x509.ExtKeyUsageClientAuth, orgs...)
+				}
+			}
+			return cfg, nil
+		},
+	}
+}
+
+// ClientTLSConfig returns a TLS config for dialing a peer that presents a
+// certificate for serverName issued by the trusted CA.
+func (id *Identity) ClientTLSConfig(serverName string) (*tls.Config, error) {
+	pool := id.CAPool()
+	if pool == nil {
+		return nil, fmt.Errorf("no trusted CA installed")
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS13,
+		ServerName: serverName,
+		RootCAs:    pool,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert := id.currentCertificate()
+			return &cert, nil
+		},
+	}, nil
+}
+
+// PinnedClientTLSConfig returns a TLS config for dialing a peer that has not
+// been issued a certificate yet. The connection is only accepted if the peer
+// presents exactly the certificate in pinned.
+func (id *Identity) PinnedClientTLSConfig(pinned []byte) *tls.Config {
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS13,
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 || !bytes.Equal(rawCerts[0], pinned) {
+				return fmt.Errorf("peer certificate does not match pinned certificate")
+			}
+			return nil
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert := id.currentCertificate()
+			return &cert, nil
+		},
+	}
+}
+
+// ProbeTLSConfig returns a TLS config that accepts any server certificate.
+// It is only meant for fetching a pending node's self-signed certificate so it
+// can be pinned for the actual adoption call.
+func (id *Identity) ProbeTLSConfig() *tls.Config {
+	cfg := id.PinnedClientTLSConfig(nil)
+	cfg.VerifyPeerCertificate = nil
+	return cfg
+}
+
+// VerifyPeer verifies a raw peer chain against pool and requires the leaf to
+// belong to one of the given organizations.
+func VerifyPeer(rawCerts [][]byte, pool *x509.CertPool, usage x509.ExtKeyUsage, orgs ...string) error {
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("no peer certificate presented")
+	}
+
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("failed to parse peer certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+
+	return verifyLeaf(certs[0], pool, usage, orgs...)
+}
+
+func verifyLeaf(leaf *x509.Certificate, pool *x509.CertPool, usage x509.ExtKeyUsage, orgs ...string) error {
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:     pool,
+		KeyUsages: []x509.ExtKeyUsage{usage},
+	}); err != nil {
+		return fmt.Errorf("peer certificate not trusted: %w", err)
+	}
+
+	if len(orgs) > 0 && !slices.ContainsFunc(leaf.Subject.Organization, func(o string) bool {
+		return slices.Contains(orgs, o)
+	}) {
+		return fmt.Errorf("peer certificate organization %v not permitted", leaf.Subject.Organization)
+	}
+
+	return nil
+}
+
+// VerifyCertificate verifies an already parsed peer certificate against the
+// CA in caPEM.
+func VerifyCertificate(leaf *x509.Certificate, caPEM []byte, usage x509.ExtKeyUsage, orgs ...string) error {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("no CA certificate found")
+	}
+	return verifyLeaf(leaf, pool, usage, orgs...)
+}
+
+func (id *Identity) currentCertificate() tls.Certificate {
+	id.mu.RLock()
+	defer id.mu.RUnlock()
+	if id.issued != nil {
+		return *id.issued
+	}
+	return id.selfSigned
+}
+
+func (id *Identity) setCA(caPEM []byte) error {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("no CA certificate found in %s", filepath.Join(id.dir, caCertFile))
+	}
+	id.caPEM = caPEM
+	id.caPool = pool
+	return nil
+}
+
+// verifyIssued checks that certPEM was issued by a CA in pool for this
+// node's key and returns it ready for use in a TLS handshake.
+func (id *Identity) verifyIssued(certPEM []byte, pool *x509.CertPool) (*tls.Certificate, error) {
+	leaf, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse node certificate: %w", err)
+	}
+
+	pub, ok := leaf.PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pub.Equal(id.key.Public()) {
+		return nil, fmt.Errorf("node certificate does not match node key")
+	}
+
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:     pool,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return nil, fmt.Errorf("node certificate not issued by CA: %w", err)
+	}
+
+	return &tls.Certificate{
+		Certificate: [][]byte{leaf.Raw},
+		PrivateKey:  id.key,
+		Leaf:        leaf,
+	}, nil
+}
+
+func loadOrCreateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		key, err := parsePrivateKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate node key: %w", err)
+	}
+
+	keyPEM, err := encodePrivateKeyPEM(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(path, keyPEM, 0o600); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func selfSignedCertificate(name string, key crypto.Signer) (tls.Certificate, error) {
+	serial, err := randomSerial()
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    now.Add(-5 * time.Minute),
+		NotAfter:     now.Add(nodeValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to create self-signed certificate: %w", err)
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to parse self-signed certificate: %w", err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, nil
+}
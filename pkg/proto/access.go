@@ -0,0 +1,48 @@
+package proto
+
+import (
+	"context"
+	"slices"
+
+	"github.com/lunarhue/metallic-flock/pkg/pki"
+	pb "github.com/lunarhue/metallic-flock/pkg/proto/adoption/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// nodeMethods are the FlockService methods adopted nodes may call on the
+// controller.
+var nodeMethods = []string{pb.FlockService_Heartbeat_FullMethodName}
+
+// NodeAccess returns server options that keep callers holding a node
+// certificate away from everything but the methods nodes need on the
+// controller. The controller's TLS config lets node certificates through, so
+// without it any adopted node could call Adopt on the controller.
+func NodeAccess() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := checkNodeAccess(ctx, info.FullMethod); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := checkNodeAccess(ss.Context(), info.FullMethod); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+}
+
+func checkNodeAccess(ctx context.Context, method string) error {
+	caller, err := peerCertificate(ctx)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	if slices.Contains(caller.Subject.Organization, pki.OrgNode) && !slices.Contains(nodeMethods, method) {
+		return status.Errorf(codes.PermissionDenied, "nodes may not call %s", method)
+	}
+	return nil
+}
@@ -2,26 +2,285 @@ package proto
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
+	"net"
+	"time"
 
 	"github.com/lunarhue/libs-go/log"
+	"github.com/lunarhue/metallic-flock/pkg/fingerprint"
 	"github.com/lunarhue/metallic-flock/pkg/k3s"
+	"github.com/lunarhue/metallic-flock/pkg/network"
+	"github.com/lunarhue/metallic-flock/pkg/pki"
 	pb "github.com/lunarhue/metallic-flock/pkg/proto/adoption/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
+// HeartbeatHandler records heartbeats on the controller and decides whether
+// the node should revert to pending.
+type HeartbeatHandler interface {
+	Heartbeat(req *pb.HeartbeatRequest) (reconfigure bool, err error)
+}
+
 type Server struct {
 	pb.UnimplementedFlockServiceServer
+
+	// NodeID is the node's stable ID. Agents register with k3s under it.
+	NodeID string
+
+	// Cluster pins the node to a cluster. Controllers of other clusters are
+	// turned away. Empty lets any controller adopt the node.
+	Cluster string
+
+	// Identity holds the node's key pair and receives the certificates
+	// delivered with the adoption request.
+	Identity *pki.Identity
+
+	// OnAdopted is called on the agent once the node joined the cluster and
+	// reports Ready.
+	OnAdopted func(req *pb.AdoptRequest)
+
+	// Net picks the addresses the node registers with k3s.
+	Net *network.Selector
+
+	// Heartbeats handles heartbeats on the controller. Agents leave it nil.
+	Heartbeats HeartbeatHandler
+
+	operations operations
 }
 
+// errNotAdoptable is returned for the adoption methods on the controller,
+// which serves heartbeats only.
+var errNotAdoptable = status.Error(codes.Unimplemented, "the controller cannot be adopted")
+
+// joinTimeout bounds how long a node may take from starting k3s to Ready.
+const joinTimeout = 5 * time.Minute
+
 func (s *Server) Adopt(ctx context.Context, req *pb.AdoptRequest) (*pb.AdoptResponse, error) {
-	log.Infof("Received ADOPT command. Role: %s, Controller: %s", req.Role, req.ControllerIp)
-	log.Infof("Cluster Token: %s", req.ClusterToken)
+	if s.Heartbeats != nil {
+		return nil, errNotAdoptable
+	}
+
+	log.Infof("Received ADOPT command. Role: %s, Controller: %s, Token: %s", req.Role, req.ControllerIp, k3s.RedactToken(req.ClusterToken))
+
+	if req.Role != k3s.RoleAgent && req.Role != k3s.RoleServer && req.Role != "" {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown role %q", req.Role)
+	}
+
+	if s.Cluster != "" && req.Cluster != s.Cluster {
+		log.Errorf("Rejected adoption from %s: controller runs cluster %q, this node is pinned to %q", req.ControllerIp, req.Cluster, s.Cluster)
+		return nil, status.Errorf(codes.FailedPrecondition, "node is pinned to cluster %q", s.Cluster)
+	}
+
+	// A repeated or concurrent request must not swap the certificates out
+	// from under the join in progress, so they are installed only once the
+	// operation is ours.
+	op, started := s.operations.start(req.Role)
+	if !started {
+		log.Infof("Adoption %s is still in progress. Not starting another one.", op.status.OperationId)
+		return &pb.AdoptResponse{Success: true, Message: "Adoption already in progress", OperationId: op.status.OperationId}, nil
+	}
+
+	if s.Identity != nil {
+		if err := s.installCertificates(ctx, req); err != nil {
+			log.Errorf("Rejected adoption from %s: %v", req.ControllerIp, err)
+			op.setPhase(pb.AdoptionPhase_ADOPTION_PHASE_FAILED, err.Error())
+			return nil, status.Errorf(codes.PermissionDenied, "adoption rejected: %v", err)
+		}
+		log.Infof("Installed node certificate issued by controller %s", req.ControllerIp)
+	}
+
+	go s.join(op, req)
+
+	return &pb.AdoptResponse{Success: true, Message: "Adoption started", OperationId: op.status.OperationId}, nil
+}
+
+func (s *Server) GetAdoptionStatus(ctx context.Context, req *pb.GetAdoptionStatusRequest) (*pb.AdoptionStatus, error) {
+	if s.Heartbeats != nil {
+		return nil, errNotAdoptable
+	}
+
+	op := s.operations.get(req.OperationId)
+	if op == nil {
+		return nil, status.Errorf(codes.NotFound, "unknown adoption %q", req.OperationId)
+	}
+
+	current, _ := op.snapshot()
+	return current, nil
+}
+
+func (s *Server) WatchAdoption(req *pb.WatchAdoptionRequest, stream pb.FlockService_WatchAdoptionServer) error {
+	if s.Heartbeats != nil {
+		return errNotAdoptable
+	}
+
+	op := s.operations.get(req.OperationId)
+	if op == nil {
+		return status.Errorf(codes.NotFound, "unknown adoption %q", req.OperationId)
+	}
 
-	k3s.StartAgent(fmt.Sprintf("https://%s:6443", req.ControllerIp), req.ClusterToken)
+	for {
+		current, changed := op.snapshot()
+		if err := stream.Send(current); err != nil {
+			return err
+		}
+		if finished(current.Phase) {
+			return nil
+		}
 
-	return &pb.AdoptResponse{Success: true, Message: "Adoption started"}, nil
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-changed:
+		}
+	}
+}
+
+// join starts k3s and follows the node until it is Ready or fails.
+func (s *Server) join(op *operation, req *pb.AdoptRequest) {
+	id := op.status.OperationId
+	unitName := k3s.UnitName(req.Role)
+	fail := func(err error) {
+		log.Errorf("Adoption %s failed: %v", id, err)
+		op.setPhase(pb.AdoptionPhase_ADOPTION_PHASE_FAILED, err.Error())
+	}
+
+	serverURL := "https://" + net.JoinHostPort(req.ControllerIp, "6443")
+	nodeIPs, err := s.Net.NodeIPs()
+	if err != nil {
+		fail(fmt.Errorf("failed to pick node IPs: %w", err))
+		return
+	}
+	if req.Role == k3s.RoleServer {
+		err = k3s.StartServer(serverURL, req.ClusterToken, s.NodeID, nodeIPs)
+	} else {
+		err = k3s.StartAgent(serverURL, req.ClusterToken, s.NodeID, nodeIPs)
+	}
+	if err != nil {
+		fail(err)
+		return
+	}
+	op.setPhase(pb.AdoptionPhase_ADOPTION_PHASE_UNIT_SPAWNED, "")
+
+	// From here on a failed join leaves a unit behind that keeps
+	// restarting with the token, and would fight the controller's retry.
+	joinFailed := fail
+	fail = func(err error) {
+		k3s.StopUnit(unitName)
+		joinFailed(err)
+	}
+
+	deadline := time.Now().Add(joinTimeout)
+	registered := false
+	for {
+		if time.Now().After(deadline) {
+			fail(fmt.Errorf("node did not become ready within %s (kubelet registered: %t, %s: %s)",
+				joinTimeout, registered, unitName, k3s.UnitStatus(unitName)))
+			return
+		}
+		time.Sleep(2 * time.Second)
+
+		if k3s.UnitStatus(unitName) == "failed" {
+			fail(fmt.Errorf("%s failed", unitName))
+			return
+		}
+
+		found, ready, err := k3s.NodeRegistration(s.NodeID)
+		if err != nil {
+			// The API server is usually unreachable for a while during the join.
+			log.Debugf("Adoption %s: %v", id, err)
+			continue
+		}
+		if ready {
+			break
+		}
+		if found && !registered {
+			registered = true
+			log.Infof("Adoption %s: kubelet registered", id)
+			op.setPhase(pb.AdoptionPhase_ADOPTION_PHASE_KUBELET_REGISTERED, "")
+		}
+	}
+
+	// Persist the adoption before reporting it, so the controller never
+	// considers the node adopted while the node itself still thinks it's
+	// pending.
+	if s.OnAdopted != nil {
+		s.OnAdopted(req)
+	}
+
+	log.Infof("Adoption %s: node is ready", id)
+	op.setPhase(pb.AdoptionPhase_ADOPTION_PHASE_READY, "")
 }
 
 func (s *Server) Heartbeat(ctx context.Context, req *pb.HeartbeatRequest) (*pb.HeartbeatResponse, error) {
-	return &pb.HeartbeatResponse{Reconfigure: false}, nil
+	if s.Heartbeats == nil {
+		return nil, status.Error(codes.Unimplemented, "this node does not accept heartbeats")
+	}
+
+	// Node certificates are issued with the node ID as common name, so a
+	// node can only ever heartbeat for itself.
+	caller, err := peerCertificate(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if caller.Subject.CommonName != req.NodeId {
+		return nil, status.Errorf(codes.PermissionDenied, "certificate for %q cannot heartbeat for %q", caller.Subject.CommonName, req.NodeId)
+	}
+
+	reconfigure, err := s.Heartbeats.Heartbeat(req)
+	if err != nil {
+		return nil, nodeError(err)
+	}
+
+	return &pb.HeartbeatResponse{Reconfigure: reconfigure}, nil
+}
+
+func (s *Server) GetFingerprint(ctx context.Context, req *pb.GetFingerprintRequest) (*pb.GetFingerprintResponse, error) {
+	fp, err := fingerprint.GetFingerprint()
+	if err != nil {
+		log.Errorf("Failed to collect fingerprint: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to collect fingerprint: %v", err)
+	}
+
+	data, err := json.Marshal(fp)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal fingerprint: %v", err)
+	}
+
+	return &pb.GetFingerprintResponse{Fingerprint: data}, nil
+}
+
+// installCertificates makes sure the caller holds a controller certificate
+// issued by the CA it is handing over, then installs the node certificate.
+func (s *Server) installCertificates(ctx context.Context, req *pb.AdoptRequest) error {
+	caller, err := peerCertificate(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := pki.VerifyCertificate(caller, req.CaCertificate, x509.ExtKeyUsageClientAuth, pki.OrgController); err != nil {
+		return fmt.Errorf("caller is not a controller of the offered CA: %w", err)
+	}
+
+	return s.Identity.Install(req.CaCertificate, req.NodeCertificate)
+}
+
+// peerCertificate returns the leaf certificate the caller presented during
+// the TLS handshake.
+func peerCertificate(ctx context.Context) (*x509.Certificate, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("no peer information in request")
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("caller did not present a client certificate")
+	}
+
+	return tlsInfo.State.PeerCertificates[0], nil
 }
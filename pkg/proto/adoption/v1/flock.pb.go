@@ -21,13 +21,75 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type AdoptionPhase int32
+
+const (
+	AdoptionPhase_ADOPTION_PHASE_UNSPECIFIED        AdoptionPhase = 0
+	AdoptionPhase_ADOPTION_PHASE_TOKEN_RECEIVED     AdoptionPhase = 1 // Certificates installed, about to start k3s
+	AdoptionPhase_ADOPTION_PHASE_UNIT_SPAWNED       AdoptionPhase = 2 // The k3s unit is running
+	AdoptionPhase_ADOPTION_PHASE_KUBELET_REGISTERED AdoptionPhase = 3 // The node object exists in the cluster
+	AdoptionPhase_ADOPTION_PHASE_READY              AdoptionPhase = 4 // The node reports Ready
+	AdoptionPhase_ADOPTION_PHASE_FAILED             AdoptionPhase = 5 // See error
+)
+
+// Enum value maps for AdoptionPhase.
+var (
+	AdoptionPhase_name = map[int32]string{
+		0: "ADOPTION_PHASE_UNSPECIFIED",
+		1: "ADOPTION_PHASE_TOKEN_RECEIVED",
+		2: "ADOPTION_PHASE_UNIT_SPAWNED",
+		3: "ADOPTION_PHASE_KUBELET_REGISTERED",
+		4: "ADOPTION_PHASE_READY",
+		5: "ADOPTION_PHASE_FAILED",
+	}
+	AdoptionPhase_value = map[string]int32{
+		"ADOPTION_PHASE_UNSPECIFIED":        0,
+		"ADOPTION_PHASE_TOKEN_RECEIVED":     1,
+		"ADOPTION_PHASE_UNIT_SPAWNED":       2,
+		"ADOPTION_PHASE_KUBELET_REGISTERED": 3,
+		"ADOPTION_PHASE_READY":              4,
+		"ADOPTION_PHASE_FAILED":             5,
+	}
+)
+
+func (x AdoptionPhase) Enum() *AdoptionPhase {
+	p := new(AdoptionPhase)
+	*p = x
+	return p
+}
+
+func (x AdoptionPhase) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AdoptionPhase) Descriptor() protoreflect.EnumDescriptor {
+	return file_adoption_v1_flock_proto_enumTypes[0].Descriptor()
+}
+
+func (AdoptionPhase) Type() protoreflect.EnumType {
+	return &file_adoption_v1_flock_proto_enumTypes[0]
+}
+
+func (x AdoptionPhase) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AdoptionPhase.Descriptor instead.
+func (AdoptionPhase) EnumDescriptor() ([]byte, []int) {
+	return file_adoption_v1_flock_proto_rawDescGZIP(), []int{0}
+}
+
 type AdoptRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ClusterToken  string                 `protobuf:"bytes,1,opt,name=cluster_token,json=clusterToken,proto3" json:"cluster_token,omitempty"`
-	ControllerIp  string                 `protobuf:"bytes,2,opt,name=controller_ip,json=controllerIp,proto3" json:"controller_ip,omitempty"`
-	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"` // "server" or "agent"
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ClusterToken    string                 `protobuf:"bytes,1,opt,name=cluster_token,json=clusterToken,proto3" json:"cluster_token,omitempty"`
+	ControllerIp    string                 `protobuf:"bytes,2,opt,name=controller_ip,json=controllerIp,proto3" json:"controller_ip,omitempty"`
+	Role            string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`                                              // "server" or "agent"
+	CaCertificate   []byte                 `protobuf:"bytes,4,opt,name=ca_certificate,json=caCertificate,proto3" json:"ca_certificate,omitempty"`       // PEM encoded cluster CA the node should trust from now on
+	NodeCertificate []byte                 `protobuf:"bytes,5,opt,name=node_certificate,json=nodeCertificate,proto3" json:"node_certificate,omitempty"` // PEM encoded certificate issued for the node's key
+	ControllerPort  uint32                 `protobuf:"varint,6,opt,name=controller_port,json=controllerPort,proto3" json:"controller_port,omitempty"`   // Port of the controller's FlockService, used for heartbeats
+	Cluster         string                 `protobuf:"bytes,7,opt,name=cluster,proto3" json:"cluster,omitempty"`                                        // Name of the controller's cluster, empty if it has none
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *AdoptRequest) Reset() {
@@ -81,10 +143,39 @@ func (x *AdoptRequest) GetRole() string {
 	return ""
 }
 
+func (x *AdoptRequest) GetCaCertificate() []byte {
+	if x != nil {
+		return x.CaCertificate
+	}
+	return nil
+}
+
+func (x *AdoptRequest) GetNodeCertificate() []byte {
+	if x != nil {
+		return x.NodeCertificate
+	}
+	return nil
+}
+
+func (x *AdoptRequest) GetControllerPort() uint32 {
+	if x != nil {
+		return x.ControllerPort
+	}
+	return 0
+}
+
+func (x *AdoptRequest) GetCluster() string {
+	if x != nil {
+		return x.Cluster
+	}
+	return ""
+}
+
 type AdoptResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	OperationId   string                 `protobuf:"bytes,3,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -133,17 +224,194 @@ func (x *AdoptResponse) GetMessage() string {
 	return ""
 }
 
-type HeartbeatRequest struct {
+func (x *AdoptResponse) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+type AdoptionStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OperationId   string                 `protobuf:"bytes,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	Phase         AdoptionPhase          `protobuf:"varint,2,opt,name=phase,proto3,enum=adoption.v1.AdoptionPhase" json:"phase,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"` // Why the adoption failed, set in ADOPTION_PHASE_FAILED
+	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // Unix time of the last phase change
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdoptionStatus) Reset() {
+	*x = AdoptionStatus{}
+	mi := &file_adoption_v1_flock_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdoptionStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdoptionStatus) ProtoMessage() {}
+
+func (x *AdoptionStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_v1_flock_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdoptionStatus.ProtoReflect.Descriptor instead.
+func (*AdoptionStatus) Descriptor() ([]byte, []int) {
+	return file_adoption_v1_flock_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AdoptionStatus) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+func (x *AdoptionStatus) GetPhase() AdoptionPhase {
+	if x != nil {
+		return x.Phase
+	}
+	return AdoptionPhase_ADOPTION_PHASE_UNSPECIFIED
+}
+
+func (x *AdoptionStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AdoptionStatus) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *AdoptionStatus) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type GetAdoptionStatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	OperationId   string                 `protobuf:"bytes,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *GetAdoptionStatusRequest) Reset() {
+	*x = GetAdoptionStatusRequest{}
+	mi := &file_adoption_v1_flock_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAdoptionStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAdoptionStatusRequest) ProtoMessage() {}
+
+func (x *GetAdoptionStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_v1_flock_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAdoptionStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetAdoptionStatusRequest) Descriptor() ([]byte, []int) {
+	return file_adoption_v1_flock_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetAdoptionStatusRequest) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+type WatchAdoptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OperationId   string                 `protobuf:"bytes,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchAdoptionRequest) Reset() {
+	*x = WatchAdoptionRequest{}
+	mi := &file_adoption_v1_flock_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchAdoptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAdoptionRequest) ProtoMessage() {}
+
+func (x *WatchAdoptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_v1_flock_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAdoptionRequest.ProtoReflect.Descriptor instead.
+func (*WatchAdoptionRequest) Descriptor() ([]byte, []int) {
+	return file_adoption_v1_flock_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WatchAdoptionRequest) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+type HeartbeatRequest struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	NodeId               string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Status               string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	K3SUnitStatus        string                 `protobuf:"bytes,3,opt,name=k3s_unit_status,json=k3sUnitStatus,proto3" json:"k3s_unit_status,omitempty"` // systemd ActiveState of the k3s unit, e.g. "active"
+	Load1                float64                `protobuf:"fixed64,4,opt,name=load1,proto3" json:"load1,omitempty"`
+	Load5                float64                `protobuf:"fixed64,5,opt,name=load5,proto3" json:"load5,omitempty"`
+	Load15               float64                `protobuf:"fixed64,6,opt,name=load15,proto3" json:"load15,omitempty"`
+	MemoryTotalBytes     uint64                 `protobuf:"varint,7,opt,name=memory_total_bytes,json=memoryTotalBytes,proto3" json:"memory_total_bytes,omitempty"`
+	MemoryAvailableBytes uint64                 `protobuf:"varint,8,opt,name=memory_available_bytes,json=memoryAvailableBytes,proto3" json:"memory_available_bytes,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
 func (x *HeartbeatRequest) Reset() {
 	*x = HeartbeatRequest{}
-	mi := &file_adoption_v1_flock_proto_msgTypes[2]
+	mi := &file_adoption_v1_flock_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -155,7 +423,7 @@ func (x *HeartbeatRequest) String() string {
 func (*HeartbeatRequest) ProtoMessage() {}
 
 func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_adoption_v1_flock_proto_msgTypes[2]
+	mi := &file_adoption_v1_flock_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -168,7 +436,7 @@ func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
 func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
-	return file_adoption_v1_flock_proto_rawDescGZIP(), []int{2}
+	return file_adoption_v1_flock_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *HeartbeatRequest) GetNodeId() string {
@@ -185,6 +453,48 @@ func (x *HeartbeatRequest) GetStatus() string {
 	return ""
 }
 
+func (x *HeartbeatRequest) GetK3SUnitStatus() string {
+	if x != nil {
+		return x.K3SUnitStatus
+	}
+	return ""
+}
+
+func (x *HeartbeatRequest) GetLoad1() float64 {
+	if x != nil {
+		return x.Load1
+	}
+	return 0
+}
+
+func (x *HeartbeatRequest) GetLoad5() float64 {
+	if x != nil {
+		return x.Load5
+	}
+	return 0
+}
+
+func (x *HeartbeatRequest) GetLoad15() float64 {
+	if x != nil {
+		return x.Load15
+	}
+	return 0
+}
+
+func (x *HeartbeatRequest) GetMemoryTotalBytes() uint64 {
+	if x != nil {
+		return x.MemoryTotalBytes
+	}
+	return 0
+}
+
+func (x *HeartbeatRequest) GetMemoryAvailableBytes() uint64 {
+	if x != nil {
+		return x.MemoryAvailableBytes
+	}
+	return 0
+}
+
 type HeartbeatResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Reconfigure   bool                   `protobuf:"varint,1,opt,name=reconfigure,proto3" json:"reconfigure,omitempty"` // If true, node should revert to pending state
@@ -194,7 +504,7 @@ type HeartbeatResponse struct {
 
 func (x *HeartbeatResponse) Reset() {
 	*x = HeartbeatResponse{}
-	mi := &file_adoption_v1_flock_proto_msgTypes[3]
+	mi := &file_adoption_v1_flock_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -206,7 +516,7 @@ func (x *HeartbeatResponse) String() string {
 func (*HeartbeatResponse) ProtoMessage() {}
 
 func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_adoption_v1_flock_proto_msgTypes[3]
+	mi := &file_adoption_v1_flock_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -219,7 +529,7 @@ func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
 func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
-	return file_adoption_v1_flock_proto_rawDescGZIP(), []int{3}
+	return file_adoption_v1_flock_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *HeartbeatResponse) GetReconfigure() bool {
@@ -229,26 +539,141 @@ func (x *HeartbeatResponse) GetReconfigure() bool {
 	return false
 }
 
+type GetFingerprintRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFingerprintRequest) Reset() {
+	*x = GetFingerprintRequest{}
+	mi := &file_adoption_v1_flock_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFingerprintRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFingerprintRequest) ProtoMessage() {}
+
+func (x *GetFingerprintRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_v1_flock_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFingerprintRequest.ProtoReflect.Descriptor instead.
+func (*GetFingerprintRequest) Descriptor() ([]byte, []int) {
+	return file_adoption_v1_flock_proto_rawDescGZIP(), []int{7}
+}
+
+type GetFingerprintResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fingerprint   []byte                 `protobuf:"bytes,1,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"` // JSON encoded fingerprint.Fingerprint
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFingerprintResponse) Reset() {
+	*x = GetFingerprintResponse{}
+	mi := &file_adoption_v1_flock_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFingerprintResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFingerprintResponse) ProtoMessage() {}
+
+func (x *GetFingerprintResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_adoption_v1_flock_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFingerprintResponse.ProtoReflect.Descriptor instead.
+func (*GetFingerprintResponse) Descriptor() ([]byte, []int) {
+	return file_adoption_v1_flock_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetFingerprintResponse) GetFingerprint() []byte {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return nil
+}
+
 var File_adoption_v1_flock_proto protoreflect.FileDescriptor
 
 const file_adoption_v1_flock_proto_rawDesc = "" +
 	"\n" +
-	"\x17adoption/v1/flock.proto\x12\vadoption.v1\"l\n" +
+	"\x17adoption/v1/flock.proto\x12\vadoption.v1\"\x81\x02\n" +
 	"\fAdoptRequest\x12#\n" +
 	"\rcluster_token\x18\x01 \x01(\tR\fclusterToken\x12#\n" +
 	"\rcontroller_ip\x18\x02 \x01(\tR\fcontrollerIp\x12\x12\n" +
-	"\x04role\x18\x03 \x01(\tR\x04role\"C\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x12%\n" +
+	"\x0eca_certificate\x18\x04 \x01(\fR\rcaCertificate\x12)\n" +
+	"\x10node_certificate\x18\x05 \x01(\fR\x0fnodeCertificate\x12'\n" +
+	"\x0fcontroller_port\x18\x06 \x01(\rR\x0econtrollerPort\x12\x18\n" +
+	"\acluster\x18\a \x01(\tR\acluster\"f\n" +
 	"\rAdoptResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"C\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12!\n" +
+	"\foperation_id\x18\x03 \x01(\tR\voperationId\"\xae\x01\n" +
+	"\x0eAdoptionStatus\x12!\n" +
+	"\foperation_id\x18\x01 \x01(\tR\voperationId\x120\n" +
+	"\x05phase\x18\x02 \x01(\x0e2\x1a.adoption.v1.AdoptionPhaseR\x05phase\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\x03R\tupdatedAt\"=\n" +
+	"\x18GetAdoptionStatusRequest\x12!\n" +
+	"\foperation_id\x18\x01 \x01(\tR\voperationId\"9\n" +
+	"\x14WatchAdoptionRequest\x12!\n" +
+	"\foperation_id\x18\x01 \x01(\tR\voperationId\"\x93\x02\n" +
 	"\x10HeartbeatRequest\x12\x17\n" +
 	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x16\n" +
-	"\x06status\x18\x02 \x01(\tR\x06status\"5\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12&\n" +
+	"\x0fk3s_unit_status\x18\x03 \x01(\tR\rk3sUnitStatus\x12\x14\n" +
+	"\x05load1\x18\x04 \x01(\x01R\x05load1\x12\x14\n" +
+	"\x05load5\x18\x05 \x01(\x01R\x05load5\x12\x16\n" +
+	"\x06load15\x18\x06 \x01(\x01R\x06load15\x12,\n" +
+	"\x12memory_total_bytes\x18\a \x01(\x04R\x10memoryTotalBytes\x124\n" +
+	"\x16memory_available_bytes\x18\b \x01(\x04R\x14memoryAvailableBytes\"5\n" +
 	"\x11HeartbeatResponse\x12 \n" +
-	"\vreconfigure\x18\x01 \x01(\bR\vreconfigure2\x9a\x01\n" +
+	"\vreconfigure\x18\x01 \x01(\bR\vreconfigure\"\x17\n" +
+	"\x15GetFingerprintRequest\":\n" +
+	"\x16GetFingerprintResponse\x12 \n" +
+	"\vfingerprint\x18\x01 \x01(\fR\vfingerprint*\xcf\x01\n" +
+	"\rAdoptionPhase\x12\x1e\n" +
+	"\x1aADOPTION_PHASE_UNSPECIFIED\x10\x00\x12!\n" +
+	"\x1dADOPTION_PHASE_TOKEN_RECEIVED\x10\x01\x12\x1f\n" +
+	"\x1bADOPTION_PHASE_UNIT_SPAWNED\x10\x02\x12%\n" +
+	"!ADOPTION_PHASE_KUBELET_REGISTERED\x10\x03\x12\x18\n" +
+	"\x14ADOPTION_PHASE_READY\x10\x04\x12\x19\n" +
+	"\x15ADOPTION_PHASE_FAILED\x10\x052\xa1\x03\n" +
 	"\fFlockService\x12>\n" +
-	"\x05Adopt\x12\x19.adoption.v1.AdoptRequest\x1a\x1a.adoption.v1.AdoptResponse\x12J\n" +
-	"\tHeartbeat\x12\x1d.adoption.v1.HeartbeatRequest\x1a\x1e.adoption.v1.HeartbeatResponseB\xaf\x01\n" +
+	"\x05Adopt\x12\x19.adoption.v1.AdoptRequest\x1a\x1a.adoption.v1.AdoptResponse\x12W\n" +
+	"\x11GetAdoptionStatus\x12%.adoption.v1.GetAdoptionStatusRequest\x1a\x1b.adoption.v1.AdoptionStatus\x12Q\n" +
+	"\rWatchAdoption\x12!.adoption.v1.WatchAdoptionRequest\x1a\x1b.adoption.v1.AdoptionStatus0\x01\x12J\n" +
+	"\tHeartbeat\x12\x1d.adoption.v1.HeartbeatRequest\x1a\x1e.adoption.v1.HeartbeatResponse\x12Y\n" +
+	"\x0eGetFingerprint\x12\".adoption.v1.GetFingerprintRequest\x1a#.adoption.v1.GetFingerprintResponseB\xaf\x01\n" +
 	"\x0fcom.adoption.v1B\n" +
 	"FlockProtoP\x01ZCgithub.com/lunarhue/metallic-flock/pkg/proto/adoption/v1;adoptionv1\xa2\x02\x03AXX\xaa\x02\vAdoption.V1\xca\x02\vAdoption\\V1\xe2\x02\x17Adoption\\V1\\GPBMetadata\xea\x02\fAdoption::V1b\x06proto3"
 
@@ -264,23 +689,37 @@ func file_adoption_v1_flock_proto_rawDescGZIP() []byte {
 	return file_adoption_v1_flock_proto_rawDescData
 }
 
-var file_adoption_v1_flock_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_adoption_v1_flock_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_adoption_v1_flock_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_adoption_v1_flock_proto_goTypes = []any{
-	(*AdoptRequest)(nil),      // 0: adoption.v1.AdoptRequest
-	(*AdoptResponse)(nil),     // 1: adoption.v1.AdoptResponse
-	(*HeartbeatRequest)(nil),  // 2: adoption.v1.HeartbeatRequest
-	(*HeartbeatResponse)(nil), // 3: adoption.v1.HeartbeatResponse
+	(AdoptionPhase)(0),               // 0: adoption.v1.AdoptionPhase
+	(*AdoptRequest)(nil),             // 1: adoption.v1.AdoptRequest
+	(*AdoptResponse)(nil),            // 2: adoption.v1.AdoptResponse
+	(*AdoptionStatus)(nil),           // 3: adoption.v1.AdoptionStatus
+	(*GetAdoptionStatusRequest)(nil), // 4: adoption.v1.GetAdoptionStatusRequest
+	(*WatchAdoptionRequest)(nil),     // 5: adoption.v1.WatchAdoptionRequest
+	(*HeartbeatRequest)(nil),         // 6: adoption.v1.HeartbeatRequest
+	(*HeartbeatResponse)(nil),        // 7: adoption.v1.HeartbeatResponse
+	(*GetFingerprintRequest)(nil),    // 8: adoption.v1.GetFingerprintRequest
+	(*GetFingerprintResponse)(nil),   // 9: adoption.v1.GetFingerprintResponse
 }
 var file_adoption_v1_flock_proto_depIdxs = []int32{
-	0, // 0: adoption.v1.FlockService.Adopt:input_type -> adoption.v1.AdoptRequest
-	2, // 1: adoption.v1.FlockService.Heartbeat:input_type -> adoption.v1.HeartbeatRequest
-	1, // 2: adoption.v1.FlockService.Adopt:output_type -> adoption.v1.AdoptResponse
-	3, // 3: adoption.v1.FlockService.Heartbeat:output_type -> adoption.v1.HeartbeatResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0, // 0: adoption.v1.AdoptionStatus.phase:type_name -> adoption.v1.AdoptionPhase
+	1, // 1: adoption.v1.FlockService.Adopt:input_type -> adoption.v1.AdoptRequest
+	4, // 2: adoption.v1.FlockService.GetAdoptionStatus:input_type -> adoption.v1.GetAdoptionStatusRequest
+	5, // 3: adoption.v1.FlockService.WatchAdoption:input_type -> adoption.v1.WatchAdoptionRequest
+	6, // 4: adoption.v1.FlockService.Heartbeat:input_type -> adoption.v1.HeartbeatRequest
+	8, // 5: adoption.v1.FlockService.GetFingerprint:input_type -> adoption.v1.GetFingerprintRequest
+	2, // 6: adoption.v1.FlockService.Adopt:output_type -> adoption.v1.AdoptResponse
+	3, // 7: adoption.v1.FlockService.GetAdoptionStatus:output_type -> adoption.v1.AdoptionStatus
+	3, // 8: adoption.v1.FlockService.WatchAdoption:output_type -> adoption.v1.AdoptionStatus
+	7, // 9: adoption.v1.FlockService.Heartbeat:output_type -> adoption.v1.HeartbeatResponse
+	9, // 10: adoption.v1.FlockService.GetFingerprint:output_type -> adoption.v1.GetFingerprintResponse
+	6, // [6:11] is the sub-list for method output_type
+	1, // [1:6] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_adoption_v1_flock_proto_init() }
@@ -293,13 +732,14 @@ func file_adoption_v1_flock_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_adoption_v1_flock_proto_rawDesc), len(file_adoption_v1_flock_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      1,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_adoption_v1_flock_proto_goTypes,
 		DependencyIndexes: file_adoption_v1_flock_proto_depIdxs,
+		EnumInfos:         file_adoption_v1_flock_proto_enumTypes,
 		MessageInfos:      file_adoption_v1_flock_proto_msgTypes,
 	}.Build()
 	File_adoption_v1_flock_proto = out.File
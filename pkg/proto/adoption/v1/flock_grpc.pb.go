@@ -19,18 +19,28 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	FlockService_Adopt_FullMethodName     = "/adoption.v1.FlockService/Adopt"
-	FlockService_Heartbeat_FullMethodName = "/adoption.v1.FlockService/Heartbeat"
+	FlockService_Adopt_FullMethodName             = "/adoption.v1.FlockService/Adopt"
+	FlockService_GetAdoptionStatus_FullMethodName = "/adoption.v1.FlockService/GetAdoptionStatus"
+	FlockService_WatchAdoption_FullMethodName     = "/adoption.v1.FlockService/WatchAdoption"
+	FlockService_Heartbeat_FullMethodName         = "/adoption.v1.FlockService/Heartbeat"
+	FlockService_GetFingerprint_FullMethodName    = "/adoption.v1.FlockService/GetFingerprint"
 )
 
 // FlockServiceClient is the client API for FlockService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type FlockServiceClient interface {
-	// Controller calls this to adopt a node
+	// Controller calls this to adopt a node. The node joins the cluster in the
+	// background; the response carries the ID of that operation.
 	Adopt(ctx context.Context, in *AdoptRequest, opts ...grpc.CallOption) (*AdoptResponse, error)
+	// Controller calls this to check on an adoption started by Adopt
+	GetAdoptionStatus(ctx context.Context, in *GetAdoptionStatusRequest, opts ...grpc.CallOption) (*AdoptionStatus, error)
+	// Controller calls this to follow an adoption until it is ready or failed
+	WatchAdoption(ctx context.Context, in *WatchAdoptionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AdoptionStatus], error)
 	// Compute nodes call this to heartbeat/check-in
 	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+	// Controller calls this to read the node's hardware fingerprint
+	GetFingerprint(ctx context.Context, in *GetFingerprintRequest, opts ...grpc.CallOption) (*GetFingerprintResponse, error)
 }
 
 type flockServiceClient struct {
@@ -51,6 +61,35 @@ func (c *flockServiceClient) Adopt(ctx context.Context, in *AdoptRequest, opts .
 	return out, nil
 }
 
+func (c *flockServiceClient) GetAdoptionStatus(ctx context.Context, in *GetAdoptionStatusRequest, opts ...grpc.CallOption) (*AdoptionStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdoptionStatus)
+	err := c.cc.Invoke(ctx, FlockService_GetAdoptionStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flockServiceClient) WatchAdoption(ctx context.Context, in *WatchAdoptionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AdoptionStatus], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FlockService_ServiceDesc.Streams[0], FlockService_WatchAdoption_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchAdoptionRequest, AdoptionStatus]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FlockService_WatchAdoptionClient = grpc.ServerStreamingClient[AdoptionStatus]
+
 func (c *flockServiceClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(HeartbeatResponse)
@@ -61,14 +100,31 @@ func (c *flockServiceClient) Heartbeat(ctx context.Context, in *HeartbeatRequest
 	return out, nil
 }
 
+func (c *flockServiceClient) GetFingerprint(ctx context.Context, in *GetFingerprintRequest, opts ...grpc.CallOption) (*GetFingerprintResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFingerprintResponse)
+	err := c.cc.Invoke(ctx, FlockService_GetFingerprint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FlockServiceServer is the server API for FlockService service.
 // All implementations must embed UnimplementedFlockServiceServer
 // for forward compatibility.
 type FlockServiceServer interface {
-	// Controller calls this to adopt a node
+	// Controller calls this to adopt a node. The node joins the cluster in the
+	// background; the response carries the ID of that operation.
 	Adopt(context.Context, *AdoptRequest) (*AdoptResponse, error)
+	// Controller calls this to check on an adoption started by Adopt
+	GetAdoptionStatus(context.Context, *GetAdoptionStatusRequest) (*AdoptionStatus, error)
+	// Controller calls this to follow an adoption until it is ready or failed
+	WatchAdoption(*WatchAdoptionRequest, grpc.ServerStreamingServer[AdoptionStatus]) error
 	// Compute nodes call this to heartbeat/check-in
 	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
+	// Controller calls this to read the node's hardware fingerprint
+	GetFingerprint(context.Context, *GetFingerprintRequest) (*GetFingerprintResponse, error)
 	mustEmbedUnimplementedFlockServiceServer()
 }
 
@@ -82,9 +138,18 @@ type UnimplementedFlockServiceServer struct{}
 func (UnimplementedFlockServiceServer) Adopt(context.Context, *AdoptRequest) (*AdoptResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Adopt not implemented")
 }
+func (UnimplementedFlockServiceServer) GetAdoptionStatus(context.Context, *GetAdoptionStatusRequest) (*AdoptionStatus, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetAdoptionStatus not implemented")
+}
+func (UnimplementedFlockServiceServer) WatchAdoption(*WatchAdoptionRequest, grpc.ServerStreamingServer[AdoptionStatus]) error {
+	return status.Error(codes.Unimplemented, "method WatchAdoption not implemented")
+}
 func (UnimplementedFlockServiceServer) Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Heartbeat not implemented")
 }
+func (UnimplementedFlockServiceServer) GetFingerprint(context.Context, *GetFingerprintRequest) (*GetFingerprintResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetFingerprint not implemented")
+}
 func (UnimplementedFlockServiceServer) mustEmbedUnimplementedFlockServiceServer() {}
 func (UnimplementedFlockServiceServer) testEmbeddedByValue()                      {}
 
@@ -124,6 +189,35 @@ func _FlockService_Adopt_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FlockService_GetAdoptionStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAdoptionStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlockServiceServer).GetAdoptionStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlockService_GetAdoptionStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlockServiceServer).GetAdoptionStatus(ctx, req.(*GetAdoptionStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlockService_WatchAdoption_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAdoptionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FlockServiceServer).WatchAdoption(m, &grpc.GenericServerStream[WatchAdoptionRequest, AdoptionStatus]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FlockService_WatchAdoptionServer = grpc.ServerStreamingServer[AdoptionStatus]
+
 func _FlockService_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(HeartbeatRequest)
 	if err := dec(in); err != nil {
@@ -142,6 +236,24 @@ func _FlockService_Heartbeat_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FlockService_GetFingerprint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFingerprintRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlockServiceServer).GetFingerprint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlockService_GetFingerprint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlockServiceServer).GetFingerprint(ctx, req.(*GetFingerprintRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // FlockService_ServiceDesc is the grpc.ServiceDesc for FlockService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,11 +265,25 @@ var FlockService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Adopt",
 			Handler:    _FlockService_Adopt_Handler,
 		},
+		{
+			MethodName: "GetAdoptionStatus",
+			Handler:    _FlockService_GetAdoptionStatus_Handler,
+		},
 		{
 			MethodName: "Heartbeat",
 			Handler:    _FlockService_Heartbeat_Handler,
 		},
+		{
+			MethodName: "GetFingerprint",
+			Handler:    _FlockService_GetFingerprint_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAdoption",
+			Handler:       _FlockService_WatchAdoption_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "adoption/v1/flock.proto",
 }
@@ -0,0 +1,230 @@
+package proto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lunarhue/metallic-flock/pkg/inventory"
+	pbc "github.com/lunarhue/metallic-flock/pkg/proto/controller/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// NodeManager is what the management API needs from the controller.
+type NodeManager interface {
+	Approve(id string) (*inventory.Node, error)
+	Reject(id string) (*inventory.Node, error)
+	Reset(id string) (*inventory.Node, error)
+	Remove(id string, force bool) error
+}
+
+// ControllerServer serves the operator-facing ControllerService. Changes go
+// through Nodes, reads and events come straight from the Inventory.
+type ControllerServer struct {
+	pbc.UnimplementedControllerServiceServer
+
+	Nodes     NodeManager
+	Inventory *inventory.Store
+}
+
+func (s *ControllerServer) ListNodes(ctx context.Context, req *pbc.ListNodesRequest) (*pbc.ListNodesResponse, error) {
+	nodes, err := s.Inventory.List()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &pbc.ListNodesResponse{}
+	for _, node := range nodes {
+		if req.State != "" && string(node.State) != req.State {
+			continue
+		}
+		resp.Nodes = append(resp.Nodes, nodeToProto(node, false))
+	}
+	return resp, nil
+}
+
+func (s *ControllerServer) GetNode(ctx context.Context, req *pbc.GetNodeRequest) (*pbc.GetNodeResponse, error) {
+	node, err := s.Inventory.Get(req.NodeId)
+	if err != nil {
+		return nil, nodeError(err)
+	}
+	return &pbc.GetNodeResponse{Node: nodeToProto(node, true)}, nil
+}
+
+func (s *ControllerServer) ApproveNode(ctx context.Context, req *pbc.ApproveNodeRequest) (*pbc.ApproveNodeResponse, error) {
+	node, err := s.Nodes.Approve(req.NodeId)
+	if err != nil {
+		return nil, nodeError(err)
+	}
+	return &pbc.ApproveNodeResponse{State: string(node.State)}, nil
+}
+
+func (s *ControllerServer) RejectNode(ctx context.Context, req *pbc.RejectNodeRequest) (*pbc.RejectNodeResponse, error) {
+	node, err := s.Nodes.Reject(req.NodeId)
+	if err != nil {
+		return nil, nodeError(err)
+	}
+	return &pbc.RejectNodeResponse{State: string(node.State)}, nil
+}
+
+func (s *ControllerServer) ResetNode(ctx context.Context, req *pbc.ResetNodeRequest) (*pbc.ResetNodeResponse, error) {
+	node, err := s.Nodes.Reset(req.NodeId)
+	if err != nil {
+		return nil, nodeError(err)
+	}
+	return &pbc.ResetNodeResponse{State: string(node.State)}, nil
+}
+
+func (s *ControllerServer) RemoveNode(ctx context.Context, req *pbc.RemoveNodeRequest) (*pbc.RemoveNodeResponse, error) {
+	if err := s.Nodes.Remove(req.NodeId, req.Force); err != nil {
+		return nil, nodeError(err)
+	}
+	return &pbc.RemoveNodeResponse{}, nil
+}
+
+func (s *ControllerServer) WatchEvents(req *pbc.WatchEventsRequest, stream pbc.ControllerService_WatchEventsServer) error {
+	events, unsubscribe := s.Inventory.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case event, ok := <-events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "event stream fell behind, watch again")
+			}
+			if err := stream.Send(eventToProto(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func nodeError(err error) error {
+	if errors.Is(err, inventory.ErrNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.FailedPrecondition, err.Error())
+}
+
+// ListenUnix listens on a Unix socket only root can connect to, replacing a
+// stale socket left behind by a previous run.
+func ListenUnix(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(path, 0o600); err != nil {
+		lis.Close()
+		return nil, fmt.Errorf("failed to restrict socket permissions: %w", err)
+	}
+
+	return lis, nil
+}
+
+// DialController connects to the management API of a controller running on
+// this machine.
+func DialController(socketPath string) (pbc.ControllerServiceClient, *grpc.ClientConn, error) {
+	conn, err := grpc.NewClient("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return pbc.NewControllerServiceClient(conn), conn, nil
+}
+
+var eventTypes = map[inventory.EventType]pbc.EventType{
+	inventory.EventAdded:   pbc.EventType_EVENT_TYPE_NODE_ADDED,
+	inventory.EventUpdated: pbc.EventType_EVENT_TYPE_NODE_UPDATED,
+	inventory.EventRemoved: pbc.EventType_EVENT_TYPE_NODE_REMOVED,
+
+	inventory.EventAppeared:    pbc.EventType_EVENT_TYPE_NODE_APPEARED,
+	inventory.EventDisappeared: pbc.EventType_EVENT_TYPE_NODE_DISAPPEARED,
+}
+
+func eventToProto(event inventory.Event) *pbc.Event {
+	return &pbc.Event{
+		Type:          eventTypes[event.Type],
+		Node:          nodeToProto(event.Node, false),
+		PreviousState: string(event.PreviousState),
+		Time:          event.Time.Unix(),
+	}
+}
+
+// nodeToProto converts an inventory node. The fingerprint is only included on
+// request, it dwarfs everything else.
+func nodeToProto(node *inventory.Node, withFingerprint bool) *pbc.Node {
+	out := &pbc.Node{
+		Id:                node.ID,
+		Hostname:          node.Hostname,
+		Ip:                node.IP,
+		Port:              uint32(node.Port),
+		Role:              node.Role,
+		State:             string(node.State),
+		Approved:          node.Approved,
+		Metadata:          node.Metadata,
+		AdoptionPhase:     node.AdoptionPhase,
+		LastError:         node.LastError,
+		FirstSeen:         unixTime(&node.FirstSeen),
+		LastSeen:          unixTime(&node.LastSeen),
+		AdoptionStartedAt: unixTime(node.AdoptionStartedAt),
+		AdoptedAt:         unixTime(node.AdoptedAt),
+		LastHeartbeat:     unixTime(node.LastHeartbeat),
+		NextAttemptAt:     unixTime(node.NextAttemptAt),
+		StateChangedAt:    unixTime(&node.StateChangedAt),
+		UpdatedAt:         unixTime(&node.UpdatedAt),
+		Present:           node.Present,
+		PresenceChangedAt: unixTime(node.PresenceChangedAt),
+	}
+
+	if node.Status != nil {
+		out.Status = &pbc.NodeStatus{
+			K3SUnit:              node.Status.K3sUnit,
+			Load1:                node.Status.Load1,
+			Load5:                node.Status.Load5,
+			Load15:               node.Status.Load15,
+			MemoryTotalBytes:     node.Status.MemoryTotalBytes,
+			MemoryAvailableBytes: node.Status.MemoryAvailableBytes,
+		}
+	}
+
+	for _, attempt := range node.Attempts {
+		out.Attempts = append(out.Attempts, &pbc.AdoptionAttempt{
+			Role:       attempt.Role,
+			StartedAt:  unixTime(&attempt.StartedAt),
+			FinishedAt: unixTime(&attempt.FinishedAt),
+			Error:      attempt.Error,
+		})
+	}
+
+	if withFingerprint && node.Fingerprint != nil {
+		if data, err := json.Marshal(node.Fingerprint); err == nil {
+			out.Fingerprint = data
+		}
+	}
+
+	return out
+}
+
+func unixTime(t *time.Time) int64 {
+	if t == nil || t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
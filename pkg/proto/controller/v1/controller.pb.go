@@ -0,0 +1,1275 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: controller/v1/controller.proto
+
+package controllerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EventType int32
+
+const (
+	EventType_EVENT_TYPE_UNSPECIFIED      EventType = 0
+	EventType_EVENT_TYPE_NODE_ADDED       EventType = 1
+	EventType_EVENT_TYPE_NODE_UPDATED     EventType = 2
+	EventType_EVENT_TYPE_NODE_REMOVED     EventType = 3
+	EventType_EVENT_TYPE_NODE_APPEARED    EventType = 4 // An update after which the node is present in discovery again
+	EventType_EVENT_TYPE_NODE_DISAPPEARED EventType = 5 // An update after which the node is no longer present in discovery
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0: "EVENT_TYPE_UNSPECIFIED",
+		1: "EVENT_TYPE_NODE_ADDED",
+		2: "EVENT_TYPE_NODE_UPDATED",
+		3: "EVENT_TYPE_NODE_REMOVED",
+		4: "EVENT_TYPE_NODE_APPEARED",
+		5: "EVENT_TYPE_NODE_DISAPPEARED",
+	}
+	EventType_value = map[string]int32{
+		"EVENT_TYPE_UNSPECIFIED":      0,
+		"EVENT_TYPE_NODE_ADDED":       1,
+		"EVENT_TYPE_NODE_UPDATED":     2,
+		"EVENT_TYPE_NODE_REMOVED":     3,
+		"EVENT_TYPE_NODE_APPEARED":    4,
+		"EVENT_TYPE_NODE_DISAPPEARED": 5,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_controller_v1_controller_proto_enumTypes[0].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_controller_v1_controller_proto_enumTypes[0]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{0}
+}
+
+// Timestamps are Unix seconds, 0 if unset.
+type Node struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Hostname          string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Ip                string                 `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	Port              uint32                 `protobuf:"varint,4,opt,name=port,proto3" json:"port,omitempty"`
+	Role              string                 `protobuf:"bytes,5,opt,name=role,proto3" json:"role,omitempty"`
+	State             string                 `protobuf:"bytes,6,opt,name=state,proto3" json:"state,omitempty"`
+	Approved          bool                   `protobuf:"varint,7,opt,name=approved,proto3" json:"approved,omitempty"`
+	Metadata          map[string]string      `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Discovery TXT records
+	Status            *NodeStatus            `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`                                                                               // Last heartbeat report, unset until the first one
+	AdoptionPhase     string                 `protobuf:"bytes,10,opt,name=adoption_phase,json=adoptionPhase,proto3" json:"adoption_phase,omitempty"`
+	LastError         string                 `protobuf:"bytes,11,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	Attempts          []*AdoptionAttempt     `protobuf:"bytes,12,rep,name=attempts,proto3" json:"attempts,omitempty"`
+	Fingerprint       []byte                 `protobuf:"bytes,13,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"` // JSON encoded fingerprint.Fingerprint, only set by GetNode
+	FirstSeen         int64                  `protobuf:"varint,14,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"`
+	LastSeen          int64                  `protobuf:"varint,15,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	AdoptionStartedAt int64                  `protobuf:"varint,16,opt,name=adoption_started_at,json=adoptionStartedAt,proto3" json:"adoption_started_at,omitempty"`
+	AdoptedAt         int64                  `protobuf:"varint,17,opt,name=adopted_at,json=adoptedAt,proto3" json:"adopted_at,omitempty"`
+	LastHeartbeat     int64                  `protobuf:"varint,18,opt,name=last_heartbeat,json=lastHeartbeat,proto3" json:"last_heartbeat,omitempty"`
+	NextAttemptAt     int64                  `protobuf:"varint,19,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	StateChangedAt    int64                  `protobuf:"varint,20,opt,name=state_changed_at,json=stateChangedAt,proto3" json:"state_changed_at,omitempty"`
+	UpdatedAt         int64                  `protobuf:"varint,21,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Present           bool                   `protobuf:"varint,22,opt,name=present,proto3" json:"present,omitempty"` // The node is advertising itself in discovery
+	PresenceChangedAt int64                  `protobuf:"varint,23,opt,name=presence_changed_at,json=presenceChangedAt,proto3" json:"presence_changed_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Node) Reset() {
+	*x = Node{}
+	mi := &file_controller_v1_controller_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Node) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Node) ProtoMessage() {}
+
+func (x *Node) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_v1_controller_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Node.ProtoReflect.Descriptor instead.
+func (*Node) Descriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Node) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Node) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *Node) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Node) GetPort() uint32 {
+	if x != nil {
+		return x.Port
+	}
+	return 0
+}
+
+func (x *Node) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *Node) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Node) GetApproved() bool {
+	if x != nil {
+		return x.Approved
+	}
+	return false
+}
+
+func (x *Node) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Node) GetStatus() *NodeStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *Node) GetAdoptionPhase() string {
+	if x != nil {
+		return x.AdoptionPhase
+	}
+	return ""
+}
+
+func (x *Node) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *Node) GetAttempts() []*AdoptionAttempt {
+	if x != nil {
+		return x.Attempts
+	}
+	return nil
+}
+
+func (x *Node) GetFingerprint() []byte {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return nil
+}
+
+func (x *Node) GetFirstSeen() int64 {
+	if x != nil {
+		return x.FirstSeen
+	}
+	return 0
+}
+
+func (x *Node) GetLastSeen() int64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return 0
+}
+
+func (x *Node) GetAdoptionStartedAt() int64 {
+	if x != nil {
+		return x.AdoptionStartedAt
+	}
+	return 0
+}
+
+func (x *Node) GetAdoptedAt() int64 {
+	if x != nil {
+		return x.AdoptedAt
+	}
+	return 0
+}
+
+func (x *Node) GetLastHeartbeat() int64 {
+	if x != nil {
+		return x.LastHeartbeat
+	}
+	return 0
+}
+
+func (x *Node) GetNextAttemptAt() int64 {
+	if x != nil {
+		return x.NextAttemptAt
+	}
+	return 0
+}
+
+func (x *Node) GetStateChangedAt() int64 {
+	if x != nil {
+		return x.StateChangedAt
+	}
+	return 0
+}
+
+func (x *Node) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *Node) GetPresent() bool {
+	if x != nil {
+		return x.Present
+	}
+	return false
+}
+
+func (x *Node) GetPresenceChangedAt() int64 {
+	if x != nil {
+		return x.PresenceChangedAt
+	}
+	return 0
+}
+
+type NodeStatus struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	K3SUnit              string                 `protobuf:"bytes,1,opt,name=k3s_unit,json=k3sUnit,proto3" json:"k3s_unit,omitempty"`
+	Load1                float64                `protobuf:"fixed64,2,opt,name=load1,proto3" json:"load1,omitempty"`
+	Load5                float64                `protobuf:"fixed64,3,opt,name=load5,proto3" json:"load5,omitempty"`
+	Load15               float64                `protobuf:"fixed64,4,opt,name=load15,proto3" json:"load15,omitempty"`
+	MemoryTotalBytes     uint64                 `protobuf:"varint,5,opt,name=memory_total_bytes,json=memoryTotalBytes,proto3" json:"memory_total_bytes,omitempty"`
+	MemoryAvailableBytes uint64                 `protobuf:"varint,6,opt,name=memory_available_bytes,json=memoryAvailableBytes,proto3" json:"memory_available_bytes,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *NodeStatus) Reset() {
+	*x = NodeStatus{}
+	mi := &file_controller_v1_controller_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeStatus) ProtoMessage() {}
+
+func (x *NodeStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_v1_controller_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeStatus.ProtoReflect.Descriptor instead.
+func (*NodeStatus) Descriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *NodeStatus) GetK3SUnit() string {
+	if x != nil {
+		return x.K3SUnit
+	}
+	return ""
+}
+
+func (x *NodeStatus) GetLoad1() float64 {
+	if x != nil {
+		return x.Load1
+	}
+	return 0
+}
+
+func (x *NodeStatus) GetLoad5() float64 {
+	if x != nil {
+		return x.Load5
+	}
+	return 0
+}
+
+func (x *NodeStatus) GetLoad15() float64 {
+	if x != nil {
+		return x.Load15
+	}
+	return 0
+}
+
+func (x *NodeStatus) GetMemoryTotalBytes() uint64 {
+	if x != nil {
+		return x.MemoryTotalBytes
+	}
+	return 0
+}
+
+func (x *NodeStatus) GetMemoryAvailableBytes() uint64 {
+	if x != nil {
+		return x.MemoryAvailableBytes
+	}
+	return 0
+}
+
+type AdoptionAttempt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	StartedAt     int64                  `protobuf:"varint,2,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt    int64                  `protobuf:"varint,3,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"` // Empty if the attempt succeeded
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdoptionAttempt) Reset() {
+	*x = AdoptionAttempt{}
+	mi := &file_controller_v1_controller_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdoptionAttempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdoptionAttempt) ProtoMessage() {}
+
+func (x *AdoptionAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_v1_controller_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdoptionAttempt.ProtoReflect.Descriptor instead.
+func (*AdoptionAttempt) Descriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AdoptionAttempt) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *AdoptionAttempt) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *AdoptionAttempt) GetFinishedAt() int64 {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return 0
+}
+
+func (x *AdoptionAttempt) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ListNodesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"` // Only list nodes in this state if set
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNodesRequest) Reset() {
+	*x = ListNodesRequest{}
+	mi := &file_controller_v1_controller_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNodesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNodesRequest) ProtoMessage() {}
+
+func (x *ListNodesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_v1_controller_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNodesRequest.ProtoReflect.Descriptor instead.
+func (*ListNodesRequest) Descriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListNodesRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+type ListNodesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nodes         []*Node                `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNodesResponse) Reset() {
+	*x = ListNodesResponse{}
+	mi := &file_controller_v1_controller_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNodesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNodesResponse) ProtoMessage() {}
+
+func (x *ListNodesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_v1_controller_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNodesResponse.ProtoReflect.Descriptor instead.
+func (*ListNodesResponse) Descriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListNodesResponse) GetNodes() []*Node {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type GetNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNodeRequest) Reset() {
+	*x = GetNodeRequest{}
+	mi := &file_controller_v1_controller_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNodeRequest) ProtoMessage() {}
+
+func (x *GetNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_v1_controller_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNodeRequest.ProtoReflect.Descriptor instead.
+func (*GetNodeRequest) Descriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetNodeRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+type GetNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Node          *Node                  `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNodeResponse) Reset() {
+	*x = GetNodeResponse{}
+	mi := &file_controller_v1_controller_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNodeResponse) ProtoMessage() {}
+
+func (x *GetNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_v1_controller_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNodeResponse.ProtoReflect.Descriptor instead.
+func (*GetNodeResponse) Descriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetNodeResponse) GetNode() *Node {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+type ApproveNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveNodeRequest) Reset() {
+	*x = ApproveNodeRequest{}
+	mi := &file_controller_v1_controller_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveNodeRequest) ProtoMessage() {}
+
+func (x *ApproveNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_v1_controller_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveNodeRequest.ProtoReflect.Descriptor instead.
+func (*ApproveNodeRequest) Descriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ApproveNodeRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+type ApproveNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"` // State of the node after approval
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveNodeResponse) Reset() {
+	*x = ApproveNodeResponse{}
+	mi := &file_controller_v1_controller_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveNodeResponse) ProtoMessage() {}
+
+func (x *ApproveNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_v1_controller_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveNodeResponse.ProtoReflect.Descriptor instead.
+func (*ApproveNodeResponse) Descriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ApproveNodeResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+type RejectNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectNodeRequest) Reset() {
+	*x = RejectNodeRequest{}
+	mi := &file_controller_v1_controller_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectNodeRequest) ProtoMessage() {}
+
+func (x *RejectNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_v1_controller_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectNodeRequest.ProtoReflect.Descriptor instead.
+func (*RejectNodeRequest) Descriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RejectNodeRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+type RejectNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"` // State of the node after rejection
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectNodeResponse) Reset() {
+	*x = RejectNodeResponse{}
+	mi := &file_controller_v1_controller_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectNodeResponse) ProtoMessage() {}
+
+func (x *RejectNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_v1_controller_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectNodeResponse.ProtoReflect.Descriptor instead.
+func (*RejectNodeResponse) Descriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RejectNodeResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+type ResetNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetNodeRequest) Reset() {
+	*x = ResetNodeRequest{}
+	mi := &file_controller_v1_controller_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetNodeRequest) ProtoMessage() {}
+
+func (x *ResetNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_v1_controller_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetNodeRequest.ProtoReflect.Descriptor instead.
+func (*ResetNodeRequest) Descriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ResetNodeRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+type ResetNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"` // State of the node after the reset was requested
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetNodeResponse) Reset() {
+	*x = ResetNodeResponse{}
+	mi := &file_controller_v1_controller_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetNodeResponse) ProtoMessage() {}
+
+func (x *ResetNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_v1_controller_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetNodeResponse.ProtoReflect.Descriptor instead.
+func (*ResetNodeResponse) Descriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ResetNodeResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+type RemoveNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Force         bool                   `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"` // Remove the node even if it is part of the cluster
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveNodeRequest) Reset() {
+	*x = RemoveNodeRequest{}
+	mi := &file_controller_v1_controller_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveNodeRequest) ProtoMessage() {}
+
+func (x *RemoveNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_v1_controller_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveNodeRequest.ProtoReflect.Descriptor instead.
+func (*RemoveNodeRequest) Descriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RemoveNodeRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *RemoveNodeRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type RemoveNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveNodeResponse) Reset() {
+	*x = RemoveNodeResponse{}
+	mi := &file_controller_v1_controller_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveNodeResponse) ProtoMessage() {}
+
+func (x *RemoveNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_v1_controller_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveNodeResponse.ProtoReflect.Descriptor instead.
+func (*RemoveNodeResponse) Descriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{14}
+}
+
+type WatchEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEventsRequest) Reset() {
+	*x = WatchEventsRequest{}
+	mi := &file_controller_v1_controller_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEventsRequest) ProtoMessage() {}
+
+func (x *WatchEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_v1_controller_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchEventsRequest) Descriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{15}
+}
+
+type Event struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          EventType              `protobuf:"varint,1,opt,name=type,proto3,enum=controller.v1.EventType" json:"type,omitempty"`
+	Node          *Node                  `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"` // The node after the change, or as it was when removed
+	PreviousState string                 `protobuf:"bytes,3,opt,name=previous_state,json=previousState,proto3" json:"previous_state,omitempty"`
+	Time          int64                  `protobuf:"varint,4,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_controller_v1_controller_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_controller_v1_controller_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_controller_v1_controller_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Event) GetType() EventType {
+	if x != nil {
+		return x.Type
+	}
+	return EventType_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *Event) GetNode() *Node {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+func (x *Event) GetPreviousState() string {
+	if x != nil {
+		return x.PreviousState
+	}
+	return ""
+}
+
+func (x *Event) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+var File_controller_v1_controller_proto protoreflect.FileDescriptor
+
+const file_controller_v1_controller_proto_rawDesc = "" +
+	"\n" +
+	"\x1econtroller/v1/controller.proto\x12\rcontroller.v1\"\xdc\x06\n" +
+	"\x04Node\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bhostname\x18\x02 \x01(\tR\bhostname\x12\x0e\n" +
+	"\x02ip\x18\x03 \x01(\tR\x02ip\x12\x12\n" +
+	"\x04port\x18\x04 \x01(\rR\x04port\x12\x12\n" +
+	"\x04role\x18\x05 \x01(\tR\x04role\x12\x14\n" +
+	"\x05state\x18\x06 \x01(\tR\x05state\x12\x1a\n" +
+	"\bapproved\x18\a \x01(\bR\bapproved\x12=\n" +
+	"\bmetadata\x18\b \x03(\v2!.controller.v1.Node.MetadataEntryR\bmetadata\x121\n" +
+	"\x06status\x18\t \x01(\v2\x19.controller.v1.NodeStatusR\x06status\x12%\n" +
+	"\x0eadoption_phase\x18\n" +
+	" \x01(\tR\radoptionPhase\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\v \x01(\tR\tlastError\x12:\n" +
+	"\battempts\x18\f \x03(\v2\x1e.controller.v1.AdoptionAttemptR\battempts\x12 \n" +
+	"\vfingerprint\x18\r \x01(\fR\vfingerprint\x12\x1d\n" +
+	"\n" +
+	"first_seen\x18\x0e \x01(\x03R\tfirstSeen\x12\x1b\n" +
+	"\tlast_seen\x18\x0f \x01(\x03R\blastSeen\x12.\n" +
+	"\x13adoption_started_at\x18\x10 \x01(\x03R\x11adoptionStartedAt\x12\x1d\n" +
+	"\n" +
+	"adopted_at\x18\x11 \x01(\x03R\tadoptedAt\x12%\n" +
+	"\x0elast_heartbeat\x18\x12 \x01(\x03R\rlastHeartbeat\x12&\n" +
+	"\x0fnext_attempt_at\x18\x13 \x01(\x03R\rnextAttemptAt\x12(\n" +
+	"\x10state_changed_at\x18\x14 \x01(\x03R\x0estateChangedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x15 \x01(\x03R\tupdatedAt\x12\x18\n" +
+	"\apresent\x18\x16 \x01(\bR\apresent\x12.\n" +
+	"\x13presence_changed_at\x18\x17 \x01(\x03R\x11presenceChangedAt\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xcf\x01\n" +
+	"\n" +
+	"NodeStatus\x12\x19\n" +
+	"\bk3s_unit\x18\x01 \x01(\tR\ak3sUnit\x12\x14\n" +
+	"\x05load1\x18\x02 \x01(\x01R\x05load1\x12\x14\n" +
+	"\x05load5\x18\x03 \x01(\x01R\x05load5\x12\x16\n" +
+	"\x06load15\x18\x04 \x01(\x01R\x06load15\x12,\n" +
+	"\x12memory_total_bytes\x18\x05 \x01(\x04R\x10memoryTotalBytes\x124\n" +
+	"\x16memory_available_bytes\x18\x06 \x01(\x04R\x14memoryAvailableBytes\"{\n" +
+	"\x0fAdoptionAttempt\x12\x12\n" +
+	"\x04role\x18\x01 \x01(\tR\x04role\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x02 \x01(\x03R\tstartedAt\x12\x1f\n" +
+	"\vfinished_at\x18\x03 \x01(\x03R\n" +
+	"finishedAt\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"(\n" +
+	"\x10ListNodesRequest\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\">\n" +
+	"\x11ListNodesResponse\x12)\n" +
+	"\x05nodes\x18\x01 \x03(\v2\x13.controller.v1.NodeR\x05nodes\")\n" +
+	"\x0eGetNodeRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\":\n" +
+	"\x0fGetNodeResponse\x12'\n" +
+	"\x04node\x18\x01 \x01(\v2\x13.controller.v1.NodeR\x04node\"-\n" +
+	"\x12ApproveNodeRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\"+\n" +
+	"\x13ApproveNodeResponse\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\",\n" +
+	"\x11RejectNodeRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\"*\n" +
+	"\x12RejectNodeResponse\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\"+\n" +
+	"\x10ResetNodeRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\")\n" +
+	"\x11ResetNodeResponse\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\"B\n" +
+	"\x11RemoveNodeRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x14\n" +
+	"\x05force\x18\x02 \x01(\bR\x05force\"\x14\n" +
+	"\x12RemoveNodeResponse\"\x14\n" +
+	"\x12WatchEventsRequest\"\x99\x01\n" +
+	"\x05Event\x12,\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x18.controller.v1.EventTypeR\x04type\x12'\n" +
+	"\x04node\x18\x02 \x01(\v2\x13.controller.v1.NodeR\x04node\x12%\n" +
+	"\x0eprevious_state\x18\x03 \x01(\tR\rpreviousState\x12\x12\n" +
+	"\x04time\x18\x04 \x01(\x03R\x04time*\xbb\x01\n" +
+	"\tEventType\x12\x1a\n" +
+	"\x16EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15EVENT_TYPE_NODE_ADDED\x10\x01\x12\x1b\n" +
+	"\x17EVENT_TYPE_NODE_UPDATED\x10\x02\x12\x1b\n" +
+	"\x17EVENT_TYPE_NODE_REMOVED\x10\x03\x12\x1c\n" +
+	"\x18EVENT_TYPE_NODE_APPEARED\x10\x04\x12\x1f\n" +
+	"\x1bEVENT_TYPE_NODE_DISAPPEARED\x10\x052\xc3\x04\n" +
+	"\x11ControllerService\x12N\n" +
+	"\tListNodes\x12\x1f.controller.v1.ListNodesRequest\x1a .controller.v1.ListNodesResponse\x12H\n" +
+	"\aGetNode\x12\x1d.controller.v1.GetNodeRequest\x1a\x1e.controller.v1.GetNodeResponse\x12T\n" +
+	"\vApproveNode\x12!.controller.v1.ApproveNodeRequest\x1a\".controller.v1.ApproveNodeResponse\x12Q\n" +
+	"\n" +
+	"RejectNode\x12 .controller.v1.RejectNodeRequest\x1a!.controller.v1.RejectNodeResponse\x12N\n" +
+	"\tResetNode\x12\x1f.controller.v1.ResetNodeRequest\x1a .controller.v1.ResetNodeResponse\x12Q\n" +
+	"\n" +
+	"RemoveNode\x12 .controller.v1.RemoveNodeRequest\x1a!.controller.v1.RemoveNodeResponse\x12H\n" +
+	"\vWatchEvents\x12!.controller.v1.WatchEventsRequest\x1a\x14.controller.v1.Event0\x01B\xc2\x01\n" +
+	"\x11com.controller.v1B\x0fControllerProtoP\x01ZGgithub.com/lunarhue/metallic-flock/pkg/proto/controller/v1;controllerv1\xa2\x02\x03CXX\xaa\x02\rController.V1\xca\x02\rController\\V1\xe2\x02\x19Controller\\V1\\GPBMetadata\xea\x02\x0eController::V1b\x06proto3"
+
+var (
+	file_controller_v1_controller_proto_rawDescOnce sync.Once
+	file_controller_v1_controller_proto_rawDescData []byte
+)
+
+func file_controller_v1_controller_proto_rawDescGZIP() []byte {
+	file_controller_v1_controller_proto_rawDescOnce.Do(func() {
+		file_controller_v1_controller_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_controller_v1_controller_proto_rawDesc), len(file_controller_v1_controller_proto_rawDesc)))
+	})
+	return file_controller_v1_controller_proto_rawDescData
+}
+
+var file_controller_v1_controller_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_controller_v1_controller_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_controller_v1_controller_proto_goTypes = []any{
+	(EventType)(0),              // 0: controller.v1.EventType
+	(*Node)(nil),                // 1: controller.v1.Node
+	(*NodeStatus)(nil),          // 2: controller.v1.NodeStatus
+	(*AdoptionAttempt)(nil),     // 3: controller.v1.AdoptionAttempt
+	(*ListNodesRequest)(nil),    // 4: controller.v1.ListNodesRequest
+	(*ListNodesResponse)(nil),   // 5: controller.v1.ListNodesResponse
+	(*GetNodeRequest)(nil),      // 6: controller.v1.GetNodeRequest
+	(*GetNodeResponse)(nil),     // 7: controller.v1.GetNodeResponse
+	(*ApproveNodeRequest)(nil),  // 8: controller.v1.ApproveNodeRequest
+	(*ApproveNodeResponse)(nil), // 9: controller.v1.ApproveNodeResponse
+	(*RejectNodeRequest)(nil),   // 10: controller.v1.RejectNodeRequest
+	(*RejectNodeResponse)(nil),  // 11: controller.v1.RejectNodeResponse
+	(*ResetNodeRequest)(nil),    // 12: controller.v1.ResetNodeRequest
+	(*ResetNodeResponse)(nil),   // 13: controller.v1.ResetNodeResponse
+	(*RemoveNodeRequest)(nil),   // 14: controller.v1.RemoveNodeRequest
+	(*RemoveNodeResponse)(nil),  // 15: controller.v1.RemoveNodeResponse
+	(*WatchEventsRequest)(nil),  // 16: controller.v1.WatchEventsRequest
+	(*Event)(nil),               // 17: controller.v1.Event
+	nil,                         // 18: controller.v1.Node.MetadataEntry
+}
+var file_controller_v1_controller_proto_depIdxs = []int32{
+	18, // 0: controller.v1.Node.metadata:type_name -> controller.v1.Node.MetadataEntry
+	2,  // 1: controller.v1.Node.status:type_name -> controller.v1.NodeStatus
+	3,  // 2: controller.v1.Node.attempts:type_name -> controller.v1.AdoptionAttempt
+	1,  // 3: controller.v1.ListNodesResponse.nodes:type_name -> controller.v1.Node
+	1,  // 4: controller.v1.GetNodeResponse.node:type_name -> controller.v1.Node
+	0,  // 5: controller.v1.Event.type:type_name -> controller.v1.EventType
+	1,  // 6: controller.v1.Event.node:type_name -> controller.v1.Node
+	4,  // 7: controller.v1.ControllerService.ListNodes:input_type -> controller.v1.ListNodesRequest
+	6,  // 8: controller.v1.ControllerService.GetNode:input_type -> controller.v1.GetNodeRequest
+	8,  // 9: controller.v1.ControllerService.ApproveNode:input_type -> controller.v1.ApproveNodeRequest
+	10, // 10: controller.v1.ControllerService.RejectNode:input_type -> controller.v1.RejectNodeRequest
+	12, // 11: controller.v1.ControllerService.ResetNode:input_type -> controller.v1.ResetNodeRequest
+	14, // 12: controller.v1.ControllerService.RemoveNode:input_type -> controller.v1.RemoveNodeRequest
+	16, // 13: controller.v1.ControllerService.WatchEvents:input_type -> controller.v1.WatchEventsRequest
+	5,  // 14: controller.v1.ControllerService.ListNodes:output_type -> controller.v1.ListNodesResponse
+	7,  // 15: controller.v1.ControllerService.GetNode:output_type -> controller.v1.GetNodeResponse
+	9,  // 16: controller.v1.ControllerService.ApproveNode:output_type -> controller.v1.ApproveNodeResponse
+	11, // 17: controller.v1.ControllerService.RejectNode:output_type -> controller.v1.RejectNodeResponse
+	13, // 18: controller.v1.ControllerService.ResetNode:output_type -> controller.v1.ResetNodeResponse
+	15, // 19: controller.v1.ControllerService.RemoveNode:output_type -> controller.v1.RemoveNodeResponse
+	17, // 20: controller.v1.ControllerService.WatchEvents:output_type -> controller.v1.Event
+	14, // [14:21] is the sub-list for method output_type
+	7,  // [7:14] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_controller_v1_controller_proto_init() }
+func file_controller_v1_controller_proto_init() {
+	if File_controller_v1_controller_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_controller_v1_controller_proto_rawDesc), len(file_controller_v1_controller_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_controller_v1_controller_proto_goTypes,
+		DependencyIndexes: file_controller_v1_controller_proto_depIdxs,
+		EnumInfos:         file_controller_v1_controller_proto_enumTypes,
+		MessageInfos:      file_controller_v1_controller_proto_msgTypes,
+	}.Build()
+	File_controller_v1_controller_proto = out.File
+	file_controller_v1_controller_proto_goTypes = nil
+	file_controller_v1_controller_proto_depIdxs = nil
+}
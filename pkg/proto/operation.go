@@ -0,0 +1,98 @@
+package proto
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	pb "github.com/lunarhue/metallic-flock/pkg/proto/adoption/v1"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+// operation is an adoption running on the agent. Watchers wait on changed,
+// which is closed and replaced whenever the status changes.
+type operation struct {
+	mu      sync.Mutex
+	status  *pb.AdoptionStatus
+	changed chan struct{}
+}
+
+// snapshot returns a copy of the current status and a channel that is closed
+// on the next change.
+func (op *operation) snapshot() (*pb.AdoptionStatus, <-chan struct{}) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return goproto.Clone(op.status).(*pb.AdoptionStatus), op.changed
+}
+
+func (op *operation) setPhase(phase pb.AdoptionPhase, errMsg string) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	op.status.Phase = phase
+	op.status.Error = errMsg
+	op.status.UpdatedAt = time.Now().Unix()
+
+	close(op.changed)
+	op.changed = make(chan struct{})
+}
+
+func (op *operation) done() bool {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return finished(op.status.Phase)
+}
+
+// finished reports whether phase is final.
+func finished(phase pb.AdoptionPhase) bool {
+	return phase == pb.AdoptionPhase_ADOPTION_PHASE_READY || phase == pb.AdoptionPhase_ADOPTION_PHASE_FAILED
+}
+
+// operations tracks the adoptions of this node. Only one runs at a time.
+type operations struct {
+	mu      sync.Mutex
+	byID    map[string]*operation
+	current *operation
+}
+
+// start registers a new operation unless one is still running, in which case
+// that one is returned with started set to false.
+func (o *operations) start(role string) (op *operation, started bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.current != nil && !o.current.done() {
+		return o.current, false
+	}
+
+	if o.byID == nil {
+		o.byID = make(map[string]*operation)
+	}
+
+	op = &operation{
+		status: &pb.AdoptionStatus{
+			OperationId: newOperationID(),
+			Phase:       pb.AdoptionPhase_ADOPTION_PHASE_TOKEN_RECEIVED,
+			Role:        role,
+			UpdatedAt:   time.Now().Unix(),
+		},
+		changed: make(chan struct{}),
+	}
+	o.byID[op.status.OperationId] = op
+	o.current = op
+
+	return op, true
+}
+
+func (o *operations) get(id string) *operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.byID[id]
+}
+
+func newOperationID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
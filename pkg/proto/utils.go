@@ -1,35 +1,23 @@
 package proto
 
 import (
-	"log"
 	"net"
 	"strconv"
 )
 
-func FindOpenPort(defaultPort int) int {
+// ListenOpenPort listens on host at defaultPort or, if that is taken, the next
+// free port above it. The listener is handed back rather than closed so the
+// port checked is the port served on. It returns a nil listener if every port
+// is taken.
+func ListenOpenPort(host string, defaultPort int) (net.Listener, int) {
 	const maxPort = 32767 // Max positive value for int16
 
 	for port := defaultPort; port <= maxPort; port++ {
-		addr := net.JoinHostPort("", strconv.Itoa(port))
-		listener, err := net.Listen("tcp", addr)
-
-		// If err is nil, the port is available
+		lis, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
 		if err == nil {
-			listener.Close()
-			return port
+			return lis, port
 		}
 	}
 
-	return 0
-}
-
-func CurrentLocalIP() string {
-	conn, err := net.Dial("udp", "8.8.8.8:80")
-	if err != nil {
-		log.Panic(err)
-	}
-	defer conn.Close()
-	localAddr := conn.LocalAddr().(*net.UDPAddr)
-
-	return localAddr.IP.String()
+	return nil, 0
 }